@@ -0,0 +1,313 @@
+package pgn
+
+import (
+	"errors"
+	"math"
+	"strings"
+)
+
+// Judgement classifies a move by how much winning chance it gave away.
+type Judgement int
+
+const (
+	GoodMove Judgement = iota
+	Inaccuracy
+	Mistake
+	Blunder
+)
+
+func (j Judgement) String() string {
+	switch j {
+	case Inaccuracy:
+		return "inaccuracy"
+	case Mistake:
+		return "mistake"
+	case Blunder:
+		return "blunder"
+	}
+
+	return "good"
+}
+
+// NAG returns the number of the NAG marking the judgement: "6" (?!) for an
+// inaccuracy, "2" (?) for a mistake, "4" (??) for a blunder and "" for a
+// good move.
+func (j Judgement) NAG() string {
+	switch j {
+	case Inaccuracy:
+		return "6"
+	case Mistake:
+		return "2"
+	case Blunder:
+		return "4"
+	}
+
+	return ""
+}
+
+// AccuracyOptions configures Game.Accuracy.
+type AccuracyOptions struct {
+	// InaccuracyThreshold, MistakeThreshold and BlunderThreshold are the
+	// win percentage points a move must lose to be judged so. Zero takes the
+	// threshold of DefaultAccuracyOptions.
+	InaccuracyThreshold float64
+	MistakeThreshold    float64
+	BlunderThreshold    float64
+	// MaxCentipawns caps evaluations before centipawn losses are computed.
+	// Zero takes the cap of DefaultAccuracyOptions.
+	MaxCentipawns int
+	// InitialEvaluation is used before the first move when the comments
+	// before it have no %eval.
+	InitialEvaluation Evaluation
+}
+
+// DefaultAccuracyOptions uses the lichess thresholds: losing 5, 10 and 15
+// percentage points of winning chances is an inaccuracy, a mistake and a
+// blunder.
+var DefaultAccuracyOptions = AccuracyOptions{
+	InaccuracyThreshold: 5,
+	MistakeThreshold:    10,
+	BlunderThreshold:    15,
+	MaxCentipawns:       1000,
+	InitialEvaluation:   Evaluation{Centipawns: 15},
+}
+
+func (opts AccuracyOptions) withDefaults() AccuracyOptions {
+	if opts.InaccuracyThreshold <= 0 {
+		opts.InaccuracyThreshold = DefaultAccuracyOptions.InaccuracyThreshold
+	}
+	if opts.MistakeThreshold <= 0 {
+		opts.MistakeThreshold = DefaultAccuracyOptions.MistakeThreshold
+	}
+	if opts.BlunderThreshold <= 0 {
+		opts.BlunderThreshold = DefaultAccuracyOptions.BlunderThreshold
+	}
+	if opts.MaxCentipawns <= 0 {
+		opts.MaxCentipawns = DefaultAccuracyOptions.MaxCentipawns
+	}
+
+	return opts
+}
+
+// PlyAccuracy is the verdict on a single evaluated ply.
+type PlyAccuracy struct {
+	Ply   int
+	Color Color
+	SAN   string
+	// CentipawnLoss is the drop of the capped evaluation from the mover's
+	// point of view.
+	CentipawnLoss int
+	// WinBefore and WinAfter are the mover's winning chances, in percent,
+	// before and after the move.
+	WinBefore float64
+	WinAfter  float64
+	Accuracy  float64
+	Judgement Judgement
+}
+
+// PlayerAccuracy summarises the play of one side.
+type PlayerAccuracy struct {
+	Color Color
+	// Moves counts the moves with an evaluation before and after them.
+	Moves        int
+	ACPL         float64
+	Accuracy     float64
+	Inaccuracies int
+	Mistakes     int
+	Blunders     int
+}
+
+// AccuracyReport is the result of Game.Accuracy.
+type AccuracyReport struct {
+	Plies []PlyAccuracy
+	White PlayerAccuracy
+	Black PlayerAccuracy
+}
+
+// Player returns the summary for color c.
+func (r *AccuracyReport) Player(c Color) PlayerAccuracy {
+	if c == White {
+		return r.White
+	}
+
+	return r.Black
+}
+
+// WinPercent returns white's winning chances, in percent, for an
+// evaluation, using the lichess model.
+func WinPercent(e Evaluation) float64 {
+	cp := float64(min(max(e.Score(), -1000), 1000))
+	return 50 + 50*(2/(1+math.Exp(-0.00368208*cp))-1)
+}
+
+// moveAccuracy is the lichess accuracy of a move that moved the mover's
+// winning chances from before to after.
+func moveAccuracy(before, after float64) float64 {
+	if after >= before {
+		return 100
+	}
+
+	accuracy := 103.1668*math.Exp(-0.04354*(before-after)) - 3.1669 + 1
+	return min(max(accuracy, 0), 100)
+}
+
+// Accuracy computes the average centipawn loss, lichess-style accuracy and
+// move judgements of both players from the %eval annotations of the game.
+// A move is only judged when the evaluation before and after it is known.
+// A checkmating move without an evaluation counts as mate.
+func (g *Game) Accuracy(opts AccuracyOptions) (*AccuracyReport, error) {
+	opts = opts.withDefaults()
+	plies := g.Plies()
+
+	evals := make([]*Evaluation, len(plies)+1)
+	initial := opts.InitialEvaluation
+	for _, c := range g.comments {
+		for _, cmd := range ParseCommands(c) {
+			if cmd.Name != "eval" {
+				continue
+			}
+			if e, err := ParseEvaluation(cmd.Value); err == nil {
+				initial = e
+			}
+		}
+	}
+	evals[0] = &initial
+
+	found := false
+	for i, p := range plies {
+		if e, ok := p.Evaluation(); ok {
+			evals[i+1] = &e
+			found = true
+		} else if strings.HasSuffix(p.SAN, "#") {
+			mate := Evaluation{Mate: 1}
+			if p.Color == Black {
+				mate.Mate = -1
+			}
+			evals[i+1] = &mate
+			found = true
+		}
+	}
+
+	if !found {
+		return nil, errors.New("game has no evaluations")
+	}
+
+	report := &AccuracyReport{
+		White: PlayerAccuracy{Color: White},
+		Black: PlayerAccuracy{Color: Black},
+	}
+
+	weights := volatilityWeights(evals)
+
+	var cpLoss, weightedSum, weightTotal, harmonicSum [2]float64
+
+	for i, p := range plies {
+		before, after := evals[i], evals[i+1]
+		if before == nil || after == nil {
+			continue
+		}
+
+		pa := PlyAccuracy{Ply: p.Index, Color: p.Color, SAN: p.SAN}
+
+		cpBefore, cpAfter := before.Score(), after.Score()
+		cpBefore = min(max(cpBefore, -opts.MaxCentipawns), opts.MaxCentipawns)
+		cpAfter = min(max(cpAfter, -opts.MaxCentipawns), opts.MaxCentipawns)
+		pa.WinBefore, pa.WinAfter = WinPercent(*before), WinPercent(*after)
+
+		if p.Color == Black {
+			cpBefore, cpAfter = -cpBefore, -cpAfter
+			pa.WinBefore, pa.WinAfter = 100-pa.WinBefore, 100-pa.WinAfter
+		}
+
+		pa.CentipawnLoss = max(cpBefore-cpAfter, 0)
+		pa.Accuracy = moveAccuracy(pa.WinBefore, pa.WinAfter)
+
+		summary := &report.White
+		if p.Color == Black {
+			summary = &report.Black
+		}
+
+		switch drop := pa.WinBefore - pa.WinAfter; {
+		case drop >= opts.BlunderThreshold:
+			pa.Judgement = Blunder
+			summary.Blunders++
+		case drop >= opts.MistakeThreshold:
+			pa.Judgement = Mistake
+			summary.Mistakes++
+		case drop >= opts.InaccuracyThreshold:
+			pa.Judgement = Inaccuracy
+			summary.Inaccuracies++
+		}
+
+		summary.Moves++
+		cpLoss[p.Color] += float64(pa.CentipawnLoss)
+		weightedSum[p.Color] += pa.Accuracy * weights[i]
+		weightTotal[p.Color] += weights[i]
+		harmonicSum[p.Color] += 1 / max(pa.Accuracy, 1)
+
+		report.Plies = append(report.Plies, pa)
+	}
+
+	for c := White; c <= Black; c++ {
+		summary := &report.White
+		if c == Black {
+			summary = &report.Black
+		}
+
+		if summary.Moves == 0 {
+			continue
+		}
+
+		n := float64(summary.Moves)
+		summary.ACPL = cpLoss[c] / n
+		summary.Accuracy = (weightedSum[c]/weightTotal[c] + n/harmonicSum[c]) / 2
+	}
+
+	return report, nil
+}
+
+// volatilityWeights weights each move by how much winning chances swing
+// around it, as lichess does, so that quiet positions count less. The
+// weight of move i is the standard deviation of the winning chances in a
+// window around it, kept between 0.5 and 12.
+func volatilityWeights(evals []*Evaluation) []float64 {
+	wins := make([]float64, len(evals))
+	last := 50.0
+	for i, e := range evals {
+		if e != nil {
+			last = WinPercent(*e)
+		}
+		wins[i] = last
+	}
+
+	moves := len(evals) - 1
+	window := min(max(moves/10, 2), 8)
+
+	weights := make([]float64, moves)
+	for i := range weights {
+		start := max(0, min(i+1-window/2, len(wins)-window))
+		end := min(start+window, len(wins))
+		weights[i] = min(max(stddev(wins[start:end]), 0.5), 12)
+	}
+
+	return weights
+}
+
+func stddev(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	mean := 0.0
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(len(values))
+
+	variance := 0.0
+	for _, v := range values {
+		variance += (v - mean) * (v - mean)
+	}
+
+	return math.Sqrt(variance / float64(len(values)))
+}
@@ -0,0 +1,141 @@
+package pgn
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+const evaluatedGame = `[Result "1-0"]
+
+1. e4 {[%eval 0.3]} e5 {[%eval 0.25]} 2. Qh5 {[%eval 0.1]} Nc6 {[%eval 0.15]}
+3. Bc4 {[%eval 0.4]} Nf6 {[%eval #1]} 4. Qxf7# 1-0`
+
+func TestWinPercent(t *testing.T) {
+	tests := []struct {
+		eval     Evaluation
+		expected float64
+	}{
+		{Evaluation{}, 50},
+		{Evaluation{Centipawns: 1000}, 97.54},
+		{Evaluation{Centipawns: 5000}, 97.54},
+		{Evaluation{Mate: -2}, 2.46},
+		{Evaluation{Centipawns: 100}, 59.1},
+	}
+
+	for _, tt := range tests {
+		if got := WinPercent(tt.eval); math.Abs(got-tt.expected) > 0.05 {
+			t.Errorf("WinPercent(%v) = %.2f, want %.2f", tt.eval, got, tt.expected)
+		}
+	}
+}
+
+func TestAccuracy(t *testing.T) {
+	game, err := New(evaluatedGame)
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+
+	report, err := game.Accuracy(DefaultAccuracyOptions)
+	if err != nil {
+		t.Fatalf("Accuracy() error: %v", err)
+	}
+
+	if len(report.Plies) != 7 {
+		t.Fatalf("len(Plies) = %d, want 7", len(report.Plies))
+	}
+
+	expectedLoss := []int{0, 0, 15, 5, 0, 960, 0}
+	for i, p := range report.Plies {
+		if p.CentipawnLoss != expectedLoss[i] {
+			t.Errorf("ply %d (%s) loss = %d, want %d", p.Ply, p.SAN, p.CentipawnLoss, expectedLoss[i])
+		}
+	}
+
+	if j := report.Plies[5].Judgement; j != Blunder || j.NAG() != "4" {
+		t.Errorf("Nf6 judgement = %v, want blunder", j)
+	}
+	if j := report.Plies[2].Judgement; j != GoodMove {
+		t.Errorf("Qh5 judgement = %v, want good", j)
+	}
+
+	white, black := report.Player(White), report.Player(Black)
+
+	if white.Moves != 4 || black.Moves != 3 {
+		t.Errorf("moves = %d, %d, want 4, 3", white.Moves, black.Moves)
+	}
+	if white.ACPL != 3.75 {
+		t.Errorf("white ACPL = %v, want 3.75", white.ACPL)
+	}
+	if math.Abs(black.ACPL-965.0/3) > 1e-9 {
+		t.Errorf("black ACPL = %v, want %v", black.ACPL, 965.0/3)
+	}
+	if black.Blunders != 1 || black.Mistakes != 0 || white.Blunders != 0 {
+		t.Errorf("blunders = %d (white) %d (black), want 0 and 1", white.Blunders, black.Blunders)
+	}
+	if white.Accuracy < 90 || white.Accuracy > 100 {
+		t.Errorf("white accuracy = %.1f, want above 90", white.Accuracy)
+	}
+	if black.Accuracy >= white.Accuracy || black.Accuracy < 0 {
+		t.Errorf("black accuracy = %.1f, want below white's %.1f", black.Accuracy, white.Accuracy)
+	}
+}
+
+func TestAccuracyThresholds(t *testing.T) {
+	game, err := New(evaluatedGame)
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+
+	opts := DefaultAccuracyOptions
+	opts.InaccuracyThreshold = 1
+	opts.BlunderThreshold = 100
+
+	report, err := game.Accuracy(opts)
+	if err != nil {
+		t.Fatalf("Accuracy() error: %v", err)
+	}
+
+	if j := report.Plies[2].Judgement; j != Inaccuracy {
+		t.Errorf("Qh5 judgement = %v, want inaccuracy", j)
+	}
+	if j := report.Plies[5].Judgement; j != Mistake {
+		t.Errorf("Nf6 judgement = %v, want mistake", j)
+	}
+
+	plain, err := New("[Result \"*\"]\n\n1. e4 e5 *")
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+	if _, err := plain.Accuracy(DefaultAccuracyOptions); err == nil {
+		t.Errorf("Accuracy() on a game without evaluations expected error")
+	}
+}
+
+func TestAccuracyZeroOptions(t *testing.T) {
+	game, err := New(evaluatedGame)
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+
+	want, err := game.Accuracy(DefaultAccuracyOptions)
+	if err != nil {
+		t.Fatalf("Accuracy() error: %v", err)
+	}
+
+	report, err := game.Accuracy(AccuracyOptions{InitialEvaluation: DefaultAccuracyOptions.InitialEvaluation})
+	if err != nil {
+		t.Fatalf("Accuracy() error: %v", err)
+	}
+	if !reflect.DeepEqual(report, want) {
+		t.Errorf("zero options report = %+v, want the defaults %+v", report, want)
+	}
+
+	report, err = game.Accuracy(AccuracyOptions{MaxCentipawns: 500})
+	if err != nil {
+		t.Fatalf("Accuracy() error: %v", err)
+	}
+	if got := report.Plies[5].Judgement; got != Blunder {
+		t.Errorf("Nf6 judgement with default thresholds = %v, want %v", got, Blunder)
+	}
+}
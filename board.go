@@ -0,0 +1,210 @@
+package pgn
+
+import "fmt"
+
+type Color int8
+
+const (
+	White Color = iota
+	Black
+)
+
+func (c Color) Other() Color {
+	return c ^ 1
+}
+
+func (c Color) String() string {
+	if c == White {
+		return "White"
+	}
+
+	return "Black"
+}
+
+type PieceType int8
+
+const (
+	NoPieceType PieceType = iota
+	Pawn
+	Knight
+	Bishop
+	Rook
+	Queen
+	King
+)
+
+var pieceLetters = [...]byte{' ', 'P', 'N', 'B', 'R', 'Q', 'K'}
+
+func (pt PieceType) Letter() string {
+	if pt == NoPieceType {
+		return ""
+	}
+
+	return string(pieceLetters[pt])
+}
+
+func pieceTypeFromLetter(ch byte) PieceType {
+	switch ch {
+	case 'P', 'p':
+		return Pawn
+	case 'N', 'n':
+		return Knight
+	case 'B', 'b':
+		return Bishop
+	case 'R', 'r':
+		return Rook
+	case 'Q', 'q':
+		return Queen
+	case 'K', 'k':
+		return King
+	default:
+		return NoPieceType
+	}
+}
+
+type Piece struct {
+	Type  PieceType
+	Color Color
+}
+
+var NoPiece = Piece{}
+
+func (p Piece) IsEmpty() bool {
+	return p.Type == NoPieceType
+}
+
+// String returns the FEN letter of the piece, upper case for White.
+func (p Piece) String() string {
+	if p.IsEmpty() {
+		return ""
+	}
+
+	ch := pieceLetters[p.Type]
+	if p.Color == Black {
+		ch += 'a' - 'A'
+	}
+
+	return string(ch)
+}
+
+func pieceFromFEN(ch byte) (Piece, bool) {
+	pt := pieceTypeFromLetter(ch)
+	if pt == NoPieceType {
+		return NoPiece, false
+	}
+
+	if 'a' <= ch && ch <= 'z' {
+		return Piece{Type: pt, Color: Black}, true
+	}
+
+	return Piece{Type: pt, Color: White}, true
+}
+
+// Square indexes the board from a1 (0) to h8 (63), rank by rank.
+type Square int8
+
+const NoSquare Square = -1
+
+func newSquare(file, rank int) Square {
+	return Square(rank*8 + file)
+}
+
+func ParseSquare(s string) (Square, error) {
+	if len(s) != 2 || s[0] < 'a' || s[0] > 'h' || s[1] < '1' || s[1] > '8' {
+		return NoSquare, fmt.Errorf("invalid square %q", s)
+	}
+
+	return newSquare(int(s[0]-'a'), int(s[1]-'1')), nil
+}
+
+func (sq Square) File() int {
+	return int(sq) % 8
+}
+
+func (sq Square) Rank() int {
+	return int(sq) / 8
+}
+
+func (sq Square) String() string {
+	if sq < 0 || sq > 63 {
+		return "-"
+	}
+
+	return string([]byte{byte('a' + sq.File()), byte('1' + sq.Rank())})
+}
+
+const (
+	kingSide  = 0
+	queenSide = 1
+)
+
+// Position is a chess position. Positions are treated as values: the move
+// methods return a new position and leave the receiver untouched.
+type Position struct {
+	board    [64]Piece
+	turn     Color
+	castling [2][2]Square // rook squares that may still castle, by color and side
+	ep       Square
+	halfmove int
+	fullmove int
+	chess960 bool
+	variant  Variant
+	pockets  [2][7]int // crazyhouse pieces in hand, by color and piece type
+	promoted [64]bool  // crazyhouse pieces that were promoted from pawns
+	checks   [2]int    // three-check checks given, by color
+}
+
+func newEmptyPosition() *Position {
+	return &Position{
+		castling: [2][2]Square{{NoSquare, NoSquare}, {NoSquare, NoSquare}},
+		ep:       NoSquare,
+		fullmove: 1,
+	}
+}
+
+func (pos *Position) copy() *Position {
+	c := *pos
+	return &c
+}
+
+func (pos *Position) PieceAt(sq Square) Piece {
+	return pos.board[sq]
+}
+
+func (pos *Position) Turn() Color {
+	return pos.turn
+}
+
+func (pos *Position) EnPassant() Square {
+	return pos.ep
+}
+
+func (pos *Position) HalfmoveClock() int {
+	return pos.halfmove
+}
+
+func (pos *Position) FullmoveNumber() int {
+	return pos.fullmove
+}
+
+func (pos *Position) IsChess960() bool {
+	return pos.chess960
+}
+
+func (pos *Position) CanCastle(c Color, kingSideCastle bool) bool {
+	if kingSideCastle {
+		return pos.castling[c][kingSide] != NoSquare
+	}
+
+	return pos.castling[c][queenSide] != NoSquare
+}
+
+func (pos *Position) kingSquare(c Color) Square {
+	for sq := Square(0); sq < 64; sq++ {
+		if pos.board[sq] == (Piece{Type: King, Color: c}) {
+			return sq
+		}
+	}
+
+	return NoSquare
+}
@@ -0,0 +1,174 @@
+package pgn
+
+import "testing"
+
+func perft(pos *Position, depth int) int {
+	if depth == 0 {
+		return 1
+	}
+
+	nodes := 0
+	for _, m := range pos.legalMoves() {
+		nodes += perft(pos.play(m), depth-1)
+	}
+
+	return nodes
+}
+
+func TestPerft(t *testing.T) {
+	tests := []struct {
+		fen      string
+		depth    int
+		expected int
+	}{
+		{StartingFEN, 3, 8902},
+		{"r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1", 2, 2039},
+		{"8/2p5/3p4/KP5r/1R3p1k/8/4P1P1/8 w - - 0 1", 3, 2812},
+		{"r3k2r/Pppp1ppp/1b3nbN/nP6/BBP1P3/q4N2/Pp1P2PP/R2Q1RK1 w kq - 0 1", 2, 264},
+	}
+
+	for _, tt := range tests {
+		pos, err := ParseFEN(tt.fen)
+		if err != nil {
+			t.Fatalf("ParseFEN(%q) error: %v", tt.fen, err)
+		}
+
+		if got := perft(pos, tt.depth); got != tt.expected {
+			t.Errorf("perft(%q, %d) = %d, want %d", tt.fen, tt.depth, got, tt.expected)
+		}
+	}
+}
+
+func TestFENRoundTrip(t *testing.T) {
+	tests := []string{
+		StartingFEN,
+		"r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1",
+		"rnbqkbnr/pp1ppppp/8/2p5/4P3/8/PPPP1PPP/RNBQKBNR w KQkq c6 0 2",
+		"8/8/8/8/8/8/8/K6k b - - 12 70",
+	}
+
+	for _, fen := range tests {
+		pos, err := ParseFEN(fen)
+		if err != nil {
+			t.Fatalf("ParseFEN(%q) error: %v", fen, err)
+		}
+
+		if got := pos.FEN(); got != fen {
+			t.Errorf("FEN() = %q, want %q", got, fen)
+		}
+	}
+}
+
+func TestInvalidFEN(t *testing.T) {
+	tests := []string{
+		"",
+		"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP w KQkq - 0 1",
+		"rnbqkbnr/pppppppp/9/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1",
+		"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR x KQkq - 0 1",
+		"rnbq1bnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQ - 0 1",
+		"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 3+3 0 1",
+		"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1 +0+0",
+	}
+
+	for _, fen := range tests {
+		if _, err := ParseFEN(fen); err == nil {
+			t.Errorf("ParseFEN(%q) expected error", fen)
+		}
+	}
+}
+
+func TestPlaySAN(t *testing.T) {
+	pos := StartingPosition()
+
+	moves := []string{"e4", "e5", "Nf3", "Nc6", "Bb5", "a6", "Bxc6", "dxc6", "O-O", "f6", "d4", "exd4", "Nxd4", "c5"}
+	for _, san := range moves {
+		next, err := pos.PlaySAN(san)
+		if err != nil {
+			t.Fatalf("PlaySAN(%q) error: %v", san, err)
+		}
+		pos = next
+	}
+
+	expected := "r1bqkbnr/1pp3pp/p4p2/2p5/3NP3/8/PPP2PPP/RNBQ1RK1 w kq - 0 8"
+	if got := pos.FEN(); got != expected {
+		t.Errorf("FEN() = %q, want %q", got, expected)
+	}
+
+	if _, err := pos.PlaySAN("Ke2"); err == nil {
+		t.Errorf("PlaySAN(%q) expected error", "Ke2")
+	}
+}
+
+func TestSANFormatting(t *testing.T) {
+	tests := []struct {
+		fen string
+		uci string
+		san string
+	}{
+		{StartingFEN, "g1f3", "Nf3"},
+		{"1k6/8/8/8/8/8/8/R3K2R w KQ - 0 1", "e1g1", "O-O"},
+		{"1k6/8/8/8/8/8/8/R3K2R w KQ - 0 1", "e1c1", "O-O-O"},
+		{"r6k/8/8/8/8/8/8/R3K2R w KQ - 0 1", "a1a8", "Rxa8+"},
+		{"6k1/5ppp/8/8/8/8/8/R3K3 w Q - 0 1", "a1a8", "Ra8#"},
+		{"4k3/8/8/8/8/8/8/R4RK1 w - - 0 1", "a1d1", "Rad1"},
+		{"4k3/8/8/8/R7/8/8/R3K3 w - - 0 1", "a1a2", "R1a2"},
+		{"4k3/8/8/8/Q6Q/8/8/Q3K3 w - - 0 1", "a4d4", "Qa4d4"},
+		{"4k3/1P6/8/8/8/8/8/4K3 w - - 0 1", "b7b8q", "b8=Q+"},
+		{"4k3/8/8/3pP3/8/8/8/4K3 w - d6 0 1", "e5d6", "exd6"},
+	}
+
+	for _, tt := range tests {
+		pos, err := ParseFEN(tt.fen)
+		if err != nil {
+			t.Fatalf("ParseFEN(%q) error: %v", tt.fen, err)
+		}
+
+		san, err := pos.UCIToSAN(tt.uci)
+		if err != nil {
+			t.Fatalf("UCIToSAN(%q) error: %v", tt.uci, err)
+		}
+		if san != tt.san {
+			t.Errorf("UCIToSAN(%q) = %q, want %q", tt.uci, san, tt.san)
+		}
+
+		uci, err := pos.SANToUCI(tt.san)
+		if err != nil {
+			t.Fatalf("SANToUCI(%q) error: %v", tt.san, err)
+		}
+		if uci != tt.uci {
+			t.Errorf("SANToUCI(%q) = %q, want %q", tt.san, uci, tt.uci)
+		}
+	}
+}
+
+func TestGamePositions(t *testing.T) {
+	input := `[Event "Setup"]
+[SetUp "1"]
+[FEN "4k3/8/8/8/8/8/4P3/4K3 b - - 0 12"]
+[Result "*"]
+
+12... Kd7 13. e4 Ke6 *`
+
+	game, err := New(input)
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+
+	if got := game.GetMove(12).Black(); got != "Kd7" {
+		t.Errorf("GetMove(12).Black() = %q, want %q", got, "Kd7")
+	}
+
+	positions, err := game.Positions()
+	if err != nil {
+		t.Fatalf("Positions() error: %v", err)
+	}
+
+	if len(positions) != 4 {
+		t.Fatalf("len(Positions()) = %d, want 4", len(positions))
+	}
+
+	expected := "8/8/4k3/8/4P3/8/8/4K3 w - - 1 14"
+	if got := positions[3].FEN(); got != expected {
+		t.Errorf("final FEN = %q, want %q", got, expected)
+	}
+}
@@ -0,0 +1,126 @@
+package pgn
+
+import "fmt"
+
+// knightPlacements lists, for the Scharnagl numbering, which two of the five
+// squares left after placing bishops and queen hold the knights.
+var knightPlacements = [10][2]int{
+	{0, 1}, {0, 2}, {0, 3}, {0, 4}, {1, 2}, {1, 3}, {1, 4}, {2, 3}, {2, 4}, {3, 4},
+}
+
+// chess960BackRank returns the white back rank of Chess960 starting position
+// n, numbered from 0 to 959 as in Scharnagl's scheme. Position 518 is the
+// standard starting position.
+func chess960BackRank(n int) ([8]PieceType, error) {
+	var rank [8]PieceType
+
+	if n < 0 || n > 959 {
+		return rank, fmt.Errorf("chess960 position number %d out of range 0-959", n)
+	}
+
+	rank[2*(n%4)+1] = Bishop
+	n /= 4
+	rank[2*(n%4)] = Bishop
+	n /= 4
+
+	empty := func() []int {
+		files := []int{}
+		for f, pt := range rank {
+			if pt == NoPieceType {
+				files = append(files, f)
+			}
+		}
+		return files
+	}
+
+	rank[empty()[n%6]] = Queen
+	n /= 6
+
+	free := empty()
+	rank[free[knightPlacements[n][0]]] = Knight
+	rank[free[knightPlacements[n][1]]] = Knight
+
+	free = empty()
+	rank[free[0]] = Rook
+	rank[free[1]] = King
+	rank[free[2]] = Rook
+
+	return rank, nil
+}
+
+// Chess960Position returns the Chess960 starting position with Scharnagl
+// number n.
+func Chess960Position(n int) (*Position, error) {
+	rank, err := chess960BackRank(n)
+	if err != nil {
+		return nil, err
+	}
+
+	pos := newEmptyPosition()
+	pos.chess960 = true
+
+	for file, pt := range rank {
+		pos.board[newSquare(file, 0)] = Piece{Type: pt, Color: White}
+		pos.board[newSquare(file, 1)] = Piece{Type: Pawn, Color: White}
+		pos.board[newSquare(file, 6)] = Piece{Type: Pawn, Color: Black}
+		pos.board[newSquare(file, 7)] = Piece{Type: pt, Color: Black}
+	}
+
+	for c := White; c <= Black; c++ {
+		king := pos.kingSquare(c)
+		pos.castling[c][kingSide] = pos.outermostRook(c, king, kingSide)
+		pos.castling[c][queenSide] = pos.outermostRook(c, king, queenSide)
+	}
+
+	return pos, nil
+}
+
+// Chess960Number returns the Scharnagl number of the position's back rank
+// arrangement. It reports false when the pieces are not on their starting
+// squares or are not arranged as a Chess960 starting position.
+func Chess960Number(pos *Position) (int, bool) {
+	for n := 0; n < 960; n++ {
+		rank, _ := chess960BackRank(n)
+
+		match := true
+		for file, pt := range rank {
+			if pos.board[newSquare(file, 0)] != (Piece{Type: pt, Color: White}) ||
+				pos.board[newSquare(file, 7)] != (Piece{Type: pt, Color: Black}) ||
+				pos.board[newSquare(file, 1)] != (Piece{Type: Pawn, Color: White}) ||
+				pos.board[newSquare(file, 6)] != (Piece{Type: Pawn, Color: Black}) {
+				match = false
+				break
+			}
+		}
+
+		if match {
+			return n, true
+		}
+	}
+
+	return 0, false
+}
+
+// IsChess960 reports whether the game is a Chess960 game, according to its
+// Variant tag.
+func (g *Game) IsChess960() bool {
+	v, _ := LookupVariant(g.GetTag("Variant"))
+	return v == Chess960
+}
+
+// SetStartingPosition records pos as the starting position of the game in the
+// SetUp and FEN tags. Chess960 positions also set the Variant tag.
+func (g *Game) SetStartingPosition(pos *Position) {
+	if pos.chess960 {
+		g.SetTag("Variant", "Chess960")
+	}
+
+	if !pos.chess960 && pos.FEN() == StartingFEN {
+		g.RemoveTag("SetUp")
+		g.RemoveTag("FEN")
+		return
+	}
+
+	g.SetTag("SetUp", "1")
+	g.SetTag("FEN", pos.FEN())
+}
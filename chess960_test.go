@@ -0,0 +1,75 @@
+package pgn
+
+import "testing"
+
+func TestChess960Position(t *testing.T) {
+	tests := []struct {
+		n   int
+		fen string
+	}{
+		{518, "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1"},
+		{0, "bbqnnrkr/pppppppp/8/8/8/8/PPPPPPPP/BBQNNRKR w KQkq - 0 1"},
+		{959, "rkrnnqbb/pppppppp/8/8/8/8/PPPPPPPP/RKRNNQBB w KQkq - 0 1"},
+	}
+
+	for _, tt := range tests {
+		pos, err := Chess960Position(tt.n)
+		if err != nil {
+			t.Fatalf("Chess960Position(%d) error: %v", tt.n, err)
+		}
+
+		if got := pos.FEN(); got != tt.fen {
+			t.Errorf("Chess960Position(%d).FEN() = %q, want %q", tt.n, got, tt.fen)
+		}
+
+		if n, ok := Chess960Number(pos); !ok || n != tt.n {
+			t.Errorf("Chess960Number() = %d, %v, want %d", n, ok, tt.n)
+		}
+	}
+
+	if _, err := Chess960Position(960); err == nil {
+		t.Errorf("Chess960Position(960) expected error")
+	}
+}
+
+func TestChess960Castling(t *testing.T) {
+	input := `[Event "Chess960"]
+[Variant "Chess960"]
+[SetUp "1"]
+[FEN "1rk2r2/pppppppp/8/8/8/8/PPPPPPPP/1RK2R2 w BFbf - 0 1"]
+[Result "*"]
+
+1. O-O O-O-O *`
+
+	game, err := New(input)
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+
+	positions, err := game.Positions()
+	if err != nil {
+		t.Fatalf("Positions() error: %v", err)
+	}
+
+	if got, want := positions[0].FEN(), "1rk2r2/pppppppp/8/8/8/8/PPPPPPPP/1RK2R2 w KQkq - 0 1"; got != want {
+		t.Errorf("starting FEN = %q, want %q", got, want)
+	}
+
+	expected := []string{
+		"1rk2r2/pppppppp/8/8/8/8/PPPPPPPP/1R3RK1 b kq - 1 1",
+		"2kr1r2/pppppppp/8/8/8/8/PPPPPPPP/1R3RK1 w - - 2 2",
+	}
+	for i, fen := range expected {
+		if got := positions[i+1].FEN(); got != fen {
+			t.Errorf("FEN after ply %d = %q, want %q", i+1, got, fen)
+		}
+	}
+
+	uci, err := positions[0].SANToUCI("O-O")
+	if err != nil {
+		t.Fatalf("SANToUCI() error: %v", err)
+	}
+	if uci != "c1f1" {
+		t.Errorf("SANToUCI(O-O) = %q, want %q", uci, "c1f1")
+	}
+}
@@ -0,0 +1,134 @@
+package pgn
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// chessComArchive is a monthly game archive as served by the chess.com
+// published-data API.
+type chessComArchive struct {
+	Games []chessComGame `json:"games"`
+}
+
+type chessComGame struct {
+	URL         string         `json:"url"`
+	PGN         string         `json:"pgn"`
+	TimeControl string         `json:"time_control"`
+	EndTime     int64          `json:"end_time"`
+	Rated       bool           `json:"rated"`
+	TimeClass   string         `json:"time_class"`
+	Rules       string         `json:"rules"`
+	InitialFEN  string         `json:"initial_setup"`
+	White       chessComPlayer `json:"white"`
+	Black       chessComPlayer `json:"black"`
+}
+
+type chessComPlayer struct {
+	Username string `json:"username"`
+	Rating   int    `json:"rating"`
+	Result   string `json:"result"`
+}
+
+// ReadChessComArchive reads a chess.com monthly archive. Each game's
+// embedded PGN is parsed, and tags it lacks are filled from the archive's
+// metadata: players, ratings, time control, date, variant, result and a
+// Link to the game. Games that cannot be converted, such as games of
+// unsupported rules like bughouse, are skipped; the other games are still
+// returned, along with an error listing the skipped ones.
+func ReadChessComArchive(r io.Reader) ([]*Game, error) {
+	var archive chessComArchive
+	if err := json.NewDecoder(r).Decode(&archive); err != nil {
+		return nil, err
+	}
+
+	games := make([]*Game, 0, len(archive.Games))
+	var skipped []error
+	for i, cg := range archive.Games {
+		g, err := cg.game()
+		if err != nil {
+			skipped = append(skipped, fmt.Errorf("game %d (%s): %v", i+1, cg.URL, err))
+			continue
+		}
+
+		games = append(games, g)
+	}
+
+	return games, errors.Join(skipped...)
+}
+
+func (cg chessComGame) game() (*Game, error) {
+	if cg.PGN == "" {
+		return nil, fmt.Errorf("game has no PGN")
+	}
+
+	parsed, err := NewGames(cg.PGN)
+	if err != nil {
+		return nil, err
+	}
+	if len(parsed) != 1 {
+		return nil, fmt.Errorf("expected 1 game in PGN, found %d", len(parsed))
+	}
+	g := parsed[0]
+
+	setMissing := func(tag, value string) {
+		if value != "" && g.GetTag(tag) == "" {
+			g.SetTag(tag, value)
+		}
+	}
+
+	setMissing("White", cg.White.Username)
+	setMissing("Black", cg.Black.Username)
+	if cg.White.Rating > 0 {
+		setMissing("WhiteElo", strconv.Itoa(cg.White.Rating))
+	}
+	if cg.Black.Rating > 0 {
+		setMissing("BlackElo", strconv.Itoa(cg.Black.Rating))
+	}
+	setMissing("TimeControl", cg.TimeControl)
+	setMissing("Link", cg.URL)
+
+	if cg.EndTime > 0 {
+		setMissing("Date", time.Unix(cg.EndTime, 0).UTC().Format("2006.01.02"))
+	}
+
+	if cg.Rules != "" && cg.Rules != "chess" {
+		v, ok := LookupVariant(cg.Rules)
+		if !ok {
+			return nil, fmt.Errorf("unsupported rules %q", cg.Rules)
+		}
+		setMissing("Variant", v.Name())
+	}
+
+	if cg.InitialFEN != "" && cg.InitialFEN != StartingFEN {
+		setMissing("SetUp", "1")
+		setMissing("FEN", cg.InitialFEN)
+	}
+
+	if result := cg.result(); result != "" && g.Result() == "" {
+		setMissing("Result", result)
+		g.SetResult(result)
+	}
+
+	return g, nil
+}
+
+func (cg chessComGame) result() string {
+	switch {
+	case cg.White.Result == "win":
+		return "1-0"
+	case cg.Black.Result == "win":
+		return "0-1"
+	}
+
+	switch cg.White.Result {
+	case "agreed", "repetition", "stalemate", "insufficient", "50move", "timevsinsufficient":
+		return "1/2-1/2"
+	}
+
+	return ""
+}
@@ -0,0 +1,87 @@
+package pgn
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadChessComArchive(t *testing.T) {
+	f, err := os.Open("testdata/chesscom_archive.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	games, err := ReadChessComArchive(f)
+	if err != nil {
+		t.Fatalf("ReadChessComArchive() error: %v", err)
+	}
+
+	if len(games) != 2 {
+		t.Fatalf("len(games) = %d, want 2", len(games))
+	}
+
+	live := games[0]
+	if live.White() != "alice" || live.Result() != "0-1" || live.TimeClass() != Blitz {
+		t.Errorf("White, Result, TimeClass = %q, %q, %v", live.White(), live.Result(), live.TimeClass())
+	}
+
+	clocks := live.TimeUsage(Black)
+	if len(clocks) != 2 || clocks[1].Clock != 2*time.Minute+57300*time.Millisecond || clocks[1].Elapsed != 1800*time.Millisecond {
+		t.Errorf("black time usage = %+v", clocks)
+	}
+
+	daily := games[1]
+	tags := map[string]string{
+		"White":       "carol",
+		"Black":       "dave",
+		"WhiteElo":    "1200",
+		"BlackElo":    "1250",
+		"TimeControl": "1/259200",
+		"Variant":     "Chess960",
+		"SetUp":       "1",
+		"Link":        "https://www.chess.com/game/daily/2000000002",
+		"Date":        "2021.01.03",
+	}
+	for name, want := range tags {
+		if got := daily.GetTag(name); got != want {
+			t.Errorf("tag %s = %q, want %q", name, got, want)
+		}
+	}
+
+	if !daily.IsChess960() || daily.Result() != "1/2-1/2" {
+		t.Errorf("IsChess960(), Result() = %v, %q", daily.IsChess960(), daily.Result())
+	}
+	if _, err := daily.Positions(); err != nil {
+		t.Errorf("Positions() error: %v", err)
+	}
+}
+
+func TestReadChessComArchiveSkipsGames(t *testing.T) {
+	input := `{"games":[
+		{"url":"bughouse","pgn":"[Result \"*\"]\n\n1. e4 *","rules":"bughouse"},
+		{"url":"chess","pgn":"[Result \"*\"]\n\n1. d4 *","rules":"chess"}
+	]}`
+
+	games, err := ReadChessComArchive(strings.NewReader(input))
+	if err == nil || !strings.Contains(err.Error(), "game 1 (bughouse)") {
+		t.Errorf("ReadChessComArchive() error = %v, want the bughouse game reported", err)
+	}
+	if len(games) != 1 || games[0].GetTag("Link") != "chess" {
+		t.Fatalf("ReadChessComArchive() = %d games, want the chess game", len(games))
+	}
+}
+
+func TestReadChessComArchiveErrors(t *testing.T) {
+	for _, input := range []string{
+		`{"games":[{"url":"u"}]}`,
+		`{"games":[{"url":"u","pgn":"[Result \"*\"]\n\n1. e4 *","rules":"bughouse"}]}`,
+		`{"games":`,
+	} {
+		if _, err := ReadChessComArchive(strings.NewReader(input)); err == nil {
+			t.Errorf("ReadChessComArchive(%s) expected error", input)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Shobhit-Nagpal/pgn"
+)
+
+func runDedupe(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("dedupe", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	report := flags.Bool("report", false, "list the groups of duplicates instead of writing the games")
+	tolerance := flags.Int("date-tolerance", pgn.DefaultDuplicateOptions.DateTolerance, "days the dates of two copies may differ by")
+	movesOnly := flags.Bool("moves-only", false, "treat games with the same moves as duplicates whatever their tags")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: pgn dedupe [-report] [flags] [files]")
+		fmt.Fprintln(stderr)
+		fmt.Fprintln(stderr, "Writes the games without duplicates, keeping the most complete copy of each.")
+		fmt.Fprintln(stderr, "Copies have the same moves and loosely matching players, dates and results.")
+		fmt.Fprintln(stderr)
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	status := 0
+	games := []*pgn.Game{}
+	err := eachGame(flags.Args(), stdin, func(g *pgn.Game) {
+		games = append(games, g)
+	}, func(name string, err error) {
+		reportError(stderr, name, err)
+		status = 1
+	})
+	if err != nil {
+		fmt.Fprintf(stderr, "pgn: %v\n", err)
+		return 2
+	}
+
+	opts := pgn.DuplicateOptions{DateTolerance: *tolerance, MovesOnly: *movesOnly}
+	out := bufio.NewWriter(stdout)
+	defer out.Flush()
+
+	if *report {
+		for _, group := range pgn.FindDuplicates(games, opts) {
+			numbers := make([]string, len(group.Games))
+			for i, n := range group.Games {
+				numbers[i] = fmt.Sprint(n + 1)
+			}
+
+			kept := games[group.Keep]
+			fmt.Fprintf(out, "games %s: %s - %s, %s (keeping %d)\n",
+				strings.Join(numbers, ", "), kept.White(), kept.Black(), kept.Date(), group.Keep+1)
+		}
+		return status
+	}
+
+	kept, _ := pgn.Dedupe(games, opts)
+	if err := pgn.WriteGames(out, kept); err != nil {
+		fmt.Fprintf(stderr, "pgn: %v\n", err)
+		return 2
+	}
+
+	return status
+}
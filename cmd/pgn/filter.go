@@ -0,0 +1,158 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Shobhit-Nagpal/pgn"
+)
+
+// filterFlags are the selection flags shared by commands that take a subset
+// of the games.
+type filterFlags struct {
+	set      *flag.FlagSet
+	player   string
+	minElo   int
+	maxElo   int
+	from     string
+	to       string
+	eco      string
+	result   string
+	minPlies int
+	maxPlies int
+	fen      string
+	material string
+}
+
+func addFilterFlags(set *flag.FlagSet) *filterFlags {
+	f := &filterFlags{set: set}
+	set.StringVar(&f.player, "player", "", "keep games of a player with either color, matching part of the name")
+	set.IntVar(&f.minElo, "min-elo", 0, "keep games where both players are rated at least this")
+	set.IntVar(&f.maxElo, "max-elo", 0, "keep games where both players are rated at most this")
+	set.StringVar(&f.from, "from", "", "keep games played on or after this date, as in 2024.01.01")
+	set.StringVar(&f.to, "to", "", "keep games played on or before this date")
+	set.StringVar(&f.eco, "eco", "", "keep games with an ECO code in a range, as in B20-B99, or a single code")
+	set.StringVar(&f.result, "result", "", "keep games with one of these comma-separated results")
+	set.IntVar(&f.minPlies, "min-plies", 0, "keep games of at least this many plies")
+	set.IntVar(&f.maxPlies, "max-plies", 0, "keep games of at most this many plies")
+	set.StringVar(&f.fen, "fen", "", "keep games that reach this position")
+	set.StringVar(&f.material, "material", "", "keep games that reach this material, as in KRP:KR")
+
+	return f
+}
+
+// filter combines the filters of the flags that were set.
+func (f *filterFlags) filter() (pgn.Filter, error) {
+	set := map[string]bool{}
+	f.set.Visit(func(fl *flag.Flag) { set[fl.Name] = true })
+
+	filters := []pgn.Filter{}
+
+	if set["player"] {
+		filters = append(filters, pgn.Player(f.player))
+	}
+
+	if set["min-elo"] || set["max-elo"] {
+		filters = append(filters, pgn.EloRange(f.minElo, f.maxElo))
+	}
+
+	if set["from"] || set["to"] {
+		var from, to pgn.Date
+		var err error
+		if set["from"] {
+			if from, err = pgn.ParseDate(f.from); err != nil {
+				return nil, err
+			}
+		}
+		if set["to"] {
+			if to, err = pgn.ParseDate(f.to); err != nil {
+				return nil, err
+			}
+		}
+		filters = append(filters, pgn.DateRange(from, to))
+	}
+
+	if set["eco"] {
+		low, high, found := strings.Cut(strings.ToUpper(f.eco), "-")
+		if !found {
+			high = low
+		}
+		filters = append(filters, pgn.ECORange(low, high))
+	}
+
+	if set["result"] {
+		filters = append(filters, pgn.Result(strings.Split(f.result, ",")...))
+	}
+
+	if set["min-plies"] || set["max-plies"] {
+		filters = append(filters, pgn.PlyCount(f.minPlies, f.maxPlies))
+	}
+
+	if set["fen"] {
+		position, err := pgn.ReachesPosition(f.fen)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, position)
+	}
+
+	if set["material"] {
+		material, err := pgn.Material(f.material)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, material)
+	}
+
+	return pgn.And(filters...), nil
+}
+
+func runFilter(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("filter", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	selection := addFilterFlags(flags)
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: pgn filter [flags] [files]")
+		fmt.Fprintln(stderr)
+		fmt.Fprintln(stderr, "Streams the games that match every flag to standard output.")
+		fmt.Fprintln(stderr)
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	filter, err := selection.filter()
+	if err != nil {
+		fmt.Fprintf(stderr, "pgn: %v\n", err)
+		return 2
+	}
+
+	out := bufio.NewWriter(stdout)
+	defer out.Flush()
+
+	status, written := 0, 0
+	err = eachGame(flags.Args(), stdin, func(g *pgn.Game) {
+		if !filter(g) {
+			return
+		}
+
+		if written > 0 {
+			out.WriteString("\n")
+		}
+		out.WriteString(g.PGN())
+		written++
+	}, func(name string, err error) {
+		reportError(stderr, name, err)
+		status = 1
+	})
+	if err != nil {
+		fmt.Fprintf(stderr, "pgn: %v\n", err)
+		return 2
+	}
+
+	return status
+}
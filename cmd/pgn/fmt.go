@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/Shobhit-Nagpal/pgn"
+)
+
+func runFmt(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("fmt", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	write := flags.Bool("w", false, "rewrite files in place instead of printing them")
+	check := flags.Bool("check", false, "list files that are not formatted and exit with status 1")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: pgn fmt [-w | --check] [files]")
+		fmt.Fprintln(stderr)
+		fmt.Fprintln(stderr, "Rewrites games in PGN export format. Formatted games go to standard")
+		fmt.Fprintln(stderr, "output unless -w is given.")
+		fmt.Fprintln(stderr)
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	if *write && len(flags.Args()) == 0 {
+		fmt.Fprintln(stderr, "pgn: fmt -w needs files to rewrite")
+		return 2
+	}
+
+	inputs, err := readInputs(flags.Args(), stdin)
+	if err != nil {
+		fmt.Fprintf(stderr, "pgn: %v\n", err)
+		return 2
+	}
+
+	status := 0
+	for _, in := range inputs {
+		formatted, err := format(in.data)
+		if err != nil {
+			fmt.Fprintf(stderr, "%s: %v\n", in.name, err)
+			status = 1
+			continue
+		}
+
+		switch {
+		case *check:
+			if !bytes.Equal(formatted, in.data) {
+				fmt.Fprintln(stdout, in.name)
+				status = 1
+			}
+		case *write:
+			if bytes.Equal(formatted, in.data) {
+				continue
+			}
+			info, err := os.Stat(in.name)
+			if err != nil {
+				fmt.Fprintf(stderr, "pgn: %v\n", err)
+				return 2
+			}
+			if err := os.WriteFile(in.name, formatted, info.Mode().Perm()); err != nil {
+				fmt.Fprintf(stderr, "pgn: %v\n", err)
+				return 2
+			}
+		default:
+			stdout.Write(formatted)
+		}
+	}
+
+	return status
+}
+
+// format parses a PGN database and writes it back in export format.
+func format(data []byte) ([]byte, error) {
+	games, err := pgn.NewGames(string(data))
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := pgn.WriteGames(&buf, games); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
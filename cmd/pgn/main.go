@@ -0,0 +1,146 @@
+// Command pgn checks, formats and queries PGN files.
+//
+// Usage:
+//
+//	pgn <command> [flags] [files]
+//
+// Files default to standard input. Run "pgn help" for the list of commands.
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/Shobhit-Nagpal/pgn"
+)
+
+type command struct {
+	name    string
+	summary string
+	run     func(args []string, stdin io.Reader, stdout, stderr io.Writer) int
+}
+
+var commands = []command{
+	{"validate", "report syntax errors and illegal moves as file:line:col", runValidate},
+	{"fmt", "rewrite games in PGN export format", runFmt},
+	{"filter", "stream the games that match a selection", runFilter},
+	{"dedupe", "remove or report duplicate games", runDedupe},
+	{"tree", "show the moves played from a position with their statistics", runTree},
+	{"stats", "report each player's scores, performance rating and openings", runStats},
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run executes a command line and returns the exit status: 0 on success, 1
+// when the input has problems and 2 for usage and I/O errors.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		usage(stderr)
+		if len(args) == 0 {
+			return 2
+		}
+		return 0
+	}
+
+	for _, c := range commands {
+		if c.name == args[0] {
+			return c.run(args[1:], stdin, stdout, stderr)
+		}
+	}
+
+	fmt.Fprintf(stderr, "pgn: unknown command %q\n", args[0])
+	usage(stderr)
+	return 2
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: pgn <command> [flags] [files]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, c := range commands {
+		fmt.Fprintf(w, "  %-10s %s\n", c.name, c.summary)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Files default to standard input. Run \"pgn <command> -h\" for its flags.")
+}
+
+// input is a named PGN source.
+type input struct {
+	name string
+	data []byte
+}
+
+// readInputs reads the named files, or standard input as "-" when there are
+// none.
+func readInputs(files []string, stdin io.Reader) ([]input, error) {
+	if len(files) == 0 {
+		data, err := io.ReadAll(stdin)
+		if err != nil {
+			return nil, err
+		}
+		return []input{{name: "-", data: data}}, nil
+	}
+
+	inputs := make([]input, 0, len(files))
+	for _, name := range files {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+		inputs = append(inputs, input{name: name, data: data})
+	}
+
+	return inputs, nil
+}
+
+// eachGame streams the games of the named files, or of standard input when
+// there are none, to fn. Games that fail to parse go to bad and are skipped.
+// The error is from opening a file.
+func eachGame(files []string, stdin io.Reader, fn func(g *pgn.Game), bad func(name string, err error)) error {
+	read := func(name string, r io.Reader) {
+		games := pgn.NewReader(r)
+		for {
+			g, err := games.Next()
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			if err != nil {
+				bad(name, err)
+				continue
+			}
+			fn(g)
+		}
+	}
+
+	if len(files) == 0 {
+		read("-", stdin)
+		return nil
+	}
+
+	for _, name := range files {
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		read(name, f)
+		f.Close()
+	}
+
+	return nil
+}
+
+// reportError prints an error about a file, as file:line:col for syntax
+// errors.
+func reportError(w io.Writer, name string, err error) {
+	var syntax *pgn.SyntaxError
+	if errors.As(err, &syntax) {
+		fmt.Fprintf(w, "%s:%v\n", name, syntax)
+		return
+	}
+
+	fmt.Fprintf(w, "%s: %v\n", name, err)
+}
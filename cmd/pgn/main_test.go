@@ -0,0 +1,284 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const messyGame = `[White "Morphy"] [Black "Amateur"] [Result "1-0"] [Event "Casual"]
+1.e4 e5 2.Nf3 d6 {Philidor}
+3.d4 Bg4 4.dxe5 Bxf3 5.Qxf3 dxe5 6.Bc4 Nf6 7.Qb3 Qe7 1-0
+`
+
+const formattedGame = `[Event "Casual"]
+[White "Morphy"]
+[Black "Amateur"]
+[Result "1-0"]
+
+1. e4 e5 2. Nf3 d6 {Philidor} 3. d4 Bg4 4. dxe5 Bxf3 5. Qxf3 dxe5 6. Bc4 Nf6
+7. Qb3 Qe7 1-0
+`
+
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func runCommand(args []string, stdin string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	status := run(args, strings.NewReader(stdin), &stdout, &stderr)
+	return status, stdout.String(), stderr.String()
+}
+
+func TestValidate(t *testing.T) {
+	dir := t.TempDir()
+	good := writeFile(t, dir, "good.pgn", messyGame)
+	bad := writeFile(t, dir, "bad.pgn", `[Result "*"]
+
+1. e4 e5 2. Ke3 *
+
+[Result "1-0"]
+
+1. d4 (1. c4 ) 1-0 extra [ *
+`)
+
+	status, stdout, _ := runCommand([]string{"validate", good, bad}, "")
+	if status != 1 {
+		t.Errorf("validate exit status = %d, want 1", status)
+	}
+
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("validate reported %d problems, want 3:\n%s", len(lines), stdout)
+	}
+	if !strings.HasPrefix(lines[0], bad+":3:13: illegal move \"Ke3\"") {
+		t.Errorf("first problem = %q", lines[0])
+	}
+	if !strings.HasPrefix(lines[1], bad+":7:28: expected next token to be SYMBOL") {
+		t.Errorf("second problem = %q", lines[1])
+	}
+	if !strings.HasPrefix(lines[2], bad+":7:28: Game termination marker does not match") {
+		t.Errorf("third problem = %q", lines[2])
+	}
+
+	if status, stdout, _ := runCommand([]string{"validate"}, messyGame); status != 0 || stdout != "" {
+		t.Errorf("validate of a valid game from stdin = %d, %q", status, stdout)
+	}
+
+	if status, _, _ := runCommand([]string{"validate", filepath.Join(dir, "missing.pgn")}, ""); status != 2 {
+		t.Errorf("validate of a missing file exit status = %d, want 2", status)
+	}
+}
+
+func TestFmt(t *testing.T) {
+	status, stdout, _ := runCommand([]string{"fmt"}, messyGame)
+	if status != 0 || stdout != formattedGame {
+		t.Errorf("fmt = %d,\n%s\nwant\n%s", status, stdout, formattedGame)
+	}
+
+	dir := t.TempDir()
+	messy := writeFile(t, dir, "messy.pgn", messyGame)
+	clean := writeFile(t, dir, "clean.pgn", formattedGame)
+
+	status, stdout, _ = runCommand([]string{"fmt", "--check", messy, clean}, "")
+	if status != 1 || stdout != messy+"\n" {
+		t.Errorf("fmt --check = %d, %q, want 1, %q", status, stdout, messy+"\n")
+	}
+
+	if status, _, stderr := runCommand([]string{"fmt", "-w", messy}, ""); status != 0 {
+		t.Fatalf("fmt -w exit status = %d: %s", status, stderr)
+	}
+
+	data, err := os.ReadFile(messy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != formattedGame {
+		t.Errorf("fmt -w wrote\n%s\nwant\n%s", data, formattedGame)
+	}
+
+	if status, stdout, _ := runCommand([]string{"fmt", "--check", messy, clean}, ""); status != 0 || stdout != "" {
+		t.Errorf("fmt --check after fmt -w = %d, %q", status, stdout)
+	}
+
+	if status, _, stderr := runCommand([]string{"fmt"}, "1. e4 ( e5"); status != 1 || stderr == "" {
+		t.Errorf("fmt of invalid input = %d, %q", status, stderr)
+	}
+}
+
+func TestUnknownCommand(t *testing.T) {
+	status, _, stderr := runCommand([]string{"frobnicate"}, "")
+	if status != 2 || !strings.Contains(stderr, `unknown command "frobnicate"`) {
+		t.Errorf("unknown command = %d, %q", status, stderr)
+	}
+}
+
+const filterDatabase = `[Event "Open"]
+[White "Carlsen, Magnus"]
+[Black "Nakamura, Hikaru"]
+[WhiteElo "2850"]
+[BlackElo "2780"]
+[ECO "C65"]
+[Result "1-0"]
+
+1. e4 e5 2. Nf3 Nc6 3. Bb5 Nf6 1-0
+
+[Event "Club"]
+[White "Smith, John"]
+[Black "Carlsen, Magnus"]
+[ECO "B20"]
+[Result "0-1"]
+
+1. e4 c5 0-1
+
+[Event "Broken"]
+[Result "1-0"]
+
+1. e4 (
+
+[Event "Blitz"]
+[White "Doe, Jane"]
+[Black "Smith, John"]
+[ECO "B22"]
+[Result "1/2-1/2"]
+
+1. e4 c5 2. c3 1/2-1/2
+`
+
+func TestFilter(t *testing.T) {
+	status, stdout, stderr := runCommand([]string{"filter", "-player", "carlsen", "-eco", "B20-B99"}, filterDatabase)
+	if status != 1 || stderr != "-:22:7: unterminated variation\n" {
+		t.Errorf("filter exit status = %d, stderr %q, want 1 for the broken game", status, stderr)
+	}
+
+	expected := `[Event "Club"]
+[White "Smith, John"]
+[Black "Carlsen, Magnus"]
+[Result "0-1"]
+[ECO "B20"]
+
+1. e4 c5 0-1
+`
+	if stdout != expected {
+		t.Errorf("filter wrote\n%s\nwant\n%s", stdout, expected)
+	}
+
+	dir := t.TempDir()
+	path := writeFile(t, dir, "games.pgn", filterDatabase)
+
+	_, stdout, _ = runCommand([]string{"filter", "-result", "1-0,1/2-1/2", "-min-plies", "3", path}, "")
+	if strings.Count(stdout, "[Event ") != 2 || !strings.Contains(stdout, `[Event "Open"]`) || !strings.Contains(stdout, `[Event "Blitz"]`) {
+		t.Errorf("filter by result and plies wrote\n%s", stdout)
+	}
+
+	if status, _, _ := runCommand([]string{"filter", "-material", "KQX"}, ""); status != 2 {
+		t.Errorf("filter with a bad material signature exit status = %d, want 2", status)
+	}
+}
+
+const duplicateDatabase = `[Event "TWIC"]
+[Date "2023.05.10"]
+[White "Carlsen, Magnus"]
+[Black "Nakamura, Hikaru"]
+[Result "1-0"]
+
+1. e4 e5 2. Nf3 1-0
+
+[Event "Other"]
+[Date "2023.05.10"]
+[White "Caruana, Fabiano"]
+[Black "Ding, Liren"]
+[Result "1/2-1/2"]
+
+1. d4 d5 1/2-1/2
+
+[Event "Norway Chess"]
+[Site "Stavanger"]
+[Date "2023.05.11"]
+[White "Magnus Carlsen"]
+[Black "Nakamura,H"]
+[Result "1-0"]
+
+1. e4 e5 2. Nf3 1-0
+`
+
+func TestDedupe(t *testing.T) {
+	status, stdout, _ := runCommand([]string{"dedupe", "-report"}, duplicateDatabase)
+	if status != 0 || stdout != "games 1, 3: Magnus Carlsen - Nakamura,H, 2023.05.11 (keeping 3)\n" {
+		t.Errorf("dedupe -report = %d, %q", status, stdout)
+	}
+
+	status, stdout, _ = runCommand([]string{"dedupe"}, duplicateDatabase)
+	if status != 0 {
+		t.Errorf("dedupe exit status = %d, want 0", status)
+	}
+	if strings.Count(stdout, "[Event ") != 2 || !strings.HasPrefix(stdout, `[Event "Norway Chess"]`) {
+		t.Errorf("dedupe wrote\n%s", stdout)
+	}
+
+	_, stdout, _ = runCommand([]string{"dedupe", "-report", "-date-tolerance", "0"}, duplicateDatabase)
+	if stdout != "" {
+		t.Errorf("dedupe -report with no date tolerance = %q, want no duplicates", stdout)
+	}
+}
+
+func TestTree(t *testing.T) {
+	status, stdout, _ := runCommand([]string{"tree", "-moves", "1. e4 e5 2. Nf3 d6"}, messyGame)
+	if status != 0 {
+		t.Errorf("tree exit status = %d, want 0", status)
+	}
+	for _, want := range []string{
+		"rnbqkbnr/ppp2ppp/3p4/4p3/4P3/5N2/PPPP1PPP/RNBQKB1R w KQkq - 0 3\n",
+		"d4             1  100.0%    0.0%    0.0%     -  -\n",
+		"Total          1  100.0%",
+	} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("tree output missing %q:\n%s", want, stdout)
+		}
+	}
+
+	status, stdout, _ = runCommand([]string{"tree", "-player", "Capablanca"}, messyGame)
+	if status != 0 || !strings.Contains(stdout, "Total          0") {
+		t.Errorf("tree with no games = %d:\n%s", status, stdout)
+	}
+
+	if status, _, _ := runCommand([]string{"tree", "-moves", "1. e5"}, messyGame); status != 2 {
+		t.Errorf("tree with an illegal prefix exit status = %d, want 2", status)
+	}
+}
+
+func TestStats(t *testing.T) {
+	status, stdout, _ := runCommand([]string{"stats"}, messyGame)
+	if status != 0 {
+		t.Errorf("stats exit status = %d, want 0", status)
+	}
+	for _, want := range []string{
+		"Morphy                       1    1.0 100.0% 100.0%   0.0%     -    7.0\n",
+		"Amateur                      1    0.0   0.0%   0.0%   0.0%     -    7.0\n",
+	} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("stats output missing %q:\n%s", want, stdout)
+		}
+	}
+
+	_, stdout, _ = runCommand([]string{"stats", "-player", "orph", "-name", "Morphy"}, messyGame)
+	for _, want := range []string{"White           1  +1 =0 -0  100.0%\n", "Openings     C41 (1)\n"} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("stats -player output missing %q:\n%s", want, stdout)
+		}
+	}
+
+	_, stdout, _ = runCommand([]string{"stats"}, messyGame+"\n"+strings.Replace(messyGame, `"Morphy"`, `"Morphy, Paul"`, 1))
+	if strings.Count(stdout, "Morphy") != 1 || !strings.Contains(stdout, "Morphy                       2") {
+		t.Errorf("stats did not merge spellings of a name:\n%s", stdout)
+	}
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Shobhit-Nagpal/pgn"
+)
+
+func runStats(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("stats", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	selection := addFilterFlags(flags)
+	name := flags.String("name", "", "report on this player, matching spellings by surname and first initial")
+	openings := flags.Int("openings", 5, "number of favorite openings to list with -name")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: pgn stats [-name player] [flags] [files]")
+		fmt.Fprintln(stderr)
+		fmt.Fprintln(stderr, "Prints a table of every player's games, scores, performance rating and")
+		fmt.Fprintln(stderr, "average game length, or a detailed report of one player with -name.")
+		fmt.Fprintln(stderr, "Other flags, such as -player, select the games counted.")
+		fmt.Fprintln(stderr)
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	filter, err := selection.filter()
+	if err != nil {
+		fmt.Fprintf(stderr, "pgn: %v\n", err)
+		return 2
+	}
+
+	status := 0
+	games := pgn.NewCollection()
+	err = eachGame(flags.Args(), stdin, func(g *pgn.Game) {
+		if filter(g) {
+			games.Add(g)
+		}
+	}, func(name string, err error) {
+		reportError(stderr, name, err)
+		status = 1
+	})
+	if err != nil {
+		fmt.Fprintf(stderr, "pgn: %v\n", err)
+		return 2
+	}
+
+	out := bufio.NewWriter(stdout)
+	defer out.Flush()
+
+	if *name != "" {
+		printPlayer(out, games.Player(*name), *openings)
+		return status
+	}
+
+	fmt.Fprintf(out, "%-24s %5s %6s %6s %6s %6s %5s %6s\n", "Player", "Games", "Points", "Score", "White", "Black", "Perf", "Moves")
+	for _, s := range games.Players() {
+		total := s.Total()
+		fmt.Fprintf(out, "%-24s %5d %6.1f %5.1f%% %5.1f%% %5.1f%% %5s %6.1f\n",
+			s.Name, total.Games, total.Points(), total.Percent(), s.White.Percent(), s.Black.Percent(),
+			performance(s), s.AverageLength())
+	}
+
+	return status
+}
+
+func printPlayer(w io.Writer, s *pgn.PlayerStats, openings int) {
+	record := func(name string, r pgn.Record) {
+		fmt.Fprintf(w, "%-12s %4d  +%d =%d -%d  %.1f%%\n", name, r.Games, r.Wins, r.Draws, r.Losses, r.Percent())
+	}
+
+	fmt.Fprintln(w, s.Name)
+	fmt.Fprintln(w)
+	record("White", s.White)
+	record("Black", s.Black)
+	record("Total", s.Total())
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "%-12s %s\n", "Performance", performance(s))
+	fmt.Fprintf(w, "%-12s %.1f moves\n", "Length", s.AverageLength())
+
+	favorites := []string{}
+	for i, o := range s.Openings() {
+		if i == openings {
+			break
+		}
+		favorites = append(favorites, fmt.Sprintf("%s (%d)", o.ECO, o.Games))
+	}
+	if len(favorites) > 0 {
+		fmt.Fprintf(w, "%-12s %s\n", "Openings", strings.Join(favorites, ", "))
+	}
+
+	fmt.Fprintln(w)
+	for _, class := range []pgn.TimeClass{pgn.Bullet, pgn.Blitz, pgn.Rapid, pgn.Classical, pgn.Untimed, pgn.UnknownTimeClass} {
+		if r, ok := s.TimeClasses[class]; ok {
+			record(class.String(), r)
+		}
+	}
+}
+
+func performance(s *pgn.PlayerStats) string {
+	if rating, ok := s.PerformanceRating(); ok {
+		return fmt.Sprint(rating)
+	}
+
+	return "-"
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/Shobhit-Nagpal/pgn"
+)
+
+func runTree(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("tree", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	selection := addFilterFlags(flags)
+	moves := flags.String("moves", "", "show the position after these moves, as in \"1. e4 c5 2. Nf3\"")
+	depth := flags.Int("depth", 0, "count only the first plies of each game, or whole games when 0")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: pgn tree [-fen fen] [-moves moves] [flags] [files]")
+		fmt.Fprintln(stderr)
+		fmt.Fprintln(stderr, "Prints the moves played from a position with their results, average Elo")
+		fmt.Fprintln(stderr, "of the player moving and last date played. The position is the starting")
+		fmt.Fprintln(stderr, "position, or -fen, followed by -moves. Other flags select the games.")
+		fmt.Fprintln(stderr)
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	filter, err := selection.filter()
+	if err != nil {
+		fmt.Fprintf(stderr, "pgn: %v\n", err)
+		return 2
+	}
+
+	root := pgn.StartingPosition()
+	if selection.fen != "" {
+		if root, err = pgn.ParseFEN(selection.fen); err != nil {
+			fmt.Fprintf(stderr, "pgn: %v\n", err)
+			return 2
+		}
+	}
+	if root, err = root.PlayLine(*moves); err != nil {
+		fmt.Fprintf(stderr, "pgn: -moves: %v\n", err)
+		return 2
+	}
+
+	status := 0
+	tree := pgn.NewOpeningTree(*depth)
+	err = eachGame(flags.Args(), stdin, func(g *pgn.Game) {
+		if !filter(g) {
+			return
+		}
+		if err := tree.Add(g); err != nil {
+			fmt.Fprintf(stderr, "%s - %s: %v\n", g.White(), g.Black(), err)
+			status = 1
+		}
+	}, func(name string, err error) {
+		reportError(stderr, name, err)
+		status = 1
+	})
+	if err != nil {
+		fmt.Fprintf(stderr, "pgn: %v\n", err)
+		return 2
+	}
+
+	out := bufio.NewWriter(stdout)
+	defer out.Flush()
+
+	stats := tree.Moves(root)
+	fmt.Fprintln(out, root.FEN())
+	fmt.Fprintln(out)
+	fmt.Fprintf(out, "%-8s %7s %7s %7s %7s %5s  %s\n", "Move", "Games", "White", "Draw", "Black", "Elo", "Last")
+	for _, m := range stats {
+		printMoveStats(out, m.SAN, m)
+	}
+	printMoveStats(out, "Total", tree.Total(root))
+
+	return status
+}
+
+func printMoveStats(w io.Writer, name string, m pgn.MoveStats) {
+	elo := "-"
+	if average, ok := m.AverageElo(); ok {
+		elo = fmt.Sprint(average)
+	}
+
+	last := "-"
+	if !m.LastPlayed.IsZero() {
+		last = m.LastPlayed.String()
+	}
+
+	fmt.Fprintf(w, "%-8s %7d %6.1f%% %6.1f%% %6.1f%% %5s  %s\n",
+		name, m.Games, m.WhitePercent(), m.DrawPercent(), m.BlackPercent(), elo, last)
+}
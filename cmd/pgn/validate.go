@@ -0,0 +1,38 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/Shobhit-Nagpal/pgn"
+)
+
+func runValidate(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("validate", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: pgn validate [files]")
+		fmt.Fprintln(stderr)
+		fmt.Fprintln(stderr, "Reports every syntax error and illegal move as file:line:col: message.")
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	inputs, err := readInputs(flags.Args(), stdin)
+	if err != nil {
+		fmt.Fprintf(stderr, "pgn: %v\n", err)
+		return 2
+	}
+
+	status := 0
+	for _, in := range inputs {
+		for _, problem := range pgn.Validate(string(in.data)) {
+			fmt.Fprintf(stdout, "%s:%v\n", in.name, problem)
+			status = 1
+		}
+	}
+
+	return status
+}
@@ -0,0 +1,274 @@
+package pgn
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Collection is an ordered set of games, such as a PGN database.
+type Collection struct {
+	Games []*Game
+}
+
+func NewCollection(games ...*Game) *Collection {
+	return &Collection{Games: append([]*Game{}, games...)}
+}
+
+// ReadCollection reads every game of a PGN database. It fails on the first
+// game that does not parse.
+func ReadCollection(r io.Reader) (*Collection, error) {
+	c := NewCollection()
+	games := NewReader(r)
+
+	for {
+		g, err := games.Next()
+		if errors.Is(err, io.EOF) {
+			return c, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		c.Games = append(c.Games, g)
+	}
+}
+
+// Merge returns a collection holding the games of every collection in turn.
+func Merge(collections ...*Collection) *Collection {
+	merged := NewCollection()
+	for _, c := range collections {
+		merged.Games = append(merged.Games, c.Games...)
+	}
+
+	return merged
+}
+
+func (c *Collection) Len() int {
+	return len(c.Games)
+}
+
+func (c *Collection) Add(games ...*Game) {
+	c.Games = append(c.Games, games...)
+}
+
+// Filter returns a collection of the games that f matches.
+func (c *Collection) Filter(f Filter) *Collection {
+	return &Collection{Games: FilterGames(c.Games, f)}
+}
+
+// Dedupe removes duplicate games, keeping the most complete copy of each,
+// and returns the groups of duplicates found.
+func (c *Collection) Dedupe(opts DuplicateOptions) []DuplicateGroup {
+	kept, groups := Dedupe(c.Games, opts)
+	c.Games = kept
+	return groups
+}
+
+// Write writes the games in PGN export format.
+func (c *Collection) Write(w io.Writer) error {
+	return WriteGames(w, c.Games)
+}
+
+// Sort orders the games by a tag. Dates, such as Date and EventDate, sort
+// as dates, tags ending in Elo as ratings and Round by its numbered parts;
+// other tags sort as text. Games missing the tag come last in either
+// direction, and the sort is stable, so sorting by several tags in turn
+// orders by the last one first.
+func (c *Collection) Sort(tag string, descending bool) {
+	compare := tagComparer(tag)
+
+	sort.SliceStable(c.Games, func(i, j int) bool {
+		a, b := c.Games[i].GetTag(tag), c.Games[j].GetTag(tag)
+		switch {
+		case isUnknownTag(a) || isUnknownTag(b):
+			return !isUnknownTag(a) && isUnknownTag(b)
+		case descending:
+			return compare(b, a) < 0
+		default:
+			return compare(a, b) < 0
+		}
+	})
+}
+
+func isUnknownTag(value string) bool {
+	return value == "" || value == "?" || value == "-" || value == "????.??.??"
+}
+
+func tagComparer(tag string) func(a, b string) int {
+	switch {
+	case strings.HasSuffix(tag, "Date"):
+		return func(a, b string) int {
+			da, _ := ParseDate(a)
+			db, _ := ParseDate(b)
+			return da.Compare(db)
+		}
+	case strings.HasSuffix(tag, "Elo"):
+		return func(a, b string) int {
+			ea, _ := parseElo(a)
+			eb, _ := parseElo(b)
+			return ea - eb
+		}
+	case tag == "Round":
+		return compareRounds
+	default:
+		return strings.Compare
+	}
+}
+
+// compareRounds orders rounds such as "2", "10" and "10.3" by their numbers.
+func compareRounds(a, b string) int {
+	pa, pb := strings.Split(a, "."), strings.Split(b, ".")
+
+	for i := 0; i < len(pa) && i < len(pb); i++ {
+		na, errA := strconv.Atoi(pa[i])
+		nb, errB := strconv.Atoi(pb[i])
+		if errA != nil || errB != nil {
+			if c := strings.Compare(pa[i], pb[i]); c != 0 {
+				return c
+			}
+			continue
+		}
+		if na != nb {
+			return na - nb
+		}
+	}
+
+	return len(pa) - len(pb)
+}
+
+// Part is one of the collections a collection is split into.
+type Part struct {
+	Name       string
+	Collection *Collection
+}
+
+// SplitByTag groups the games by the value of a tag, in order of first
+// appearance. Games missing the tag go to a part named "unknown".
+func (c *Collection) SplitByTag(tag string) []Part {
+	return c.split(func(g *Game) []string {
+		return []string{g.GetTag(tag)}
+	})
+}
+
+// SplitByEvent groups the games by their Event tag.
+func (c *Collection) SplitByEvent() []Part {
+	return c.SplitByTag("Event")
+}
+
+// SplitByECO groups the games by their ECO code.
+func (c *Collection) SplitByECO() []Part {
+	return c.SplitByTag("ECO")
+}
+
+// SplitByPlayer groups the games by player. Every game goes into the parts of
+// both its players.
+func (c *Collection) SplitByPlayer() []Part {
+	return c.split(func(g *Game) []string {
+		return []string{g.White(), g.Black()}
+	})
+}
+
+// SplitBySize cuts the collection into parts of at most size games, named
+// "1", "2" and so on.
+func (c *Collection) SplitBySize(size int) []Part {
+	parts := []Part{}
+	if size <= 0 {
+		return parts
+	}
+
+	for start := 0; start < len(c.Games); start += size {
+		end := min(start+size, len(c.Games))
+		parts = append(parts, Part{
+			Name:       strconv.Itoa(len(parts) + 1),
+			Collection: NewCollection(c.Games[start:end]...),
+		})
+	}
+
+	return parts
+}
+
+func (c *Collection) split(keys func(g *Game) []string) []Part {
+	parts := []Part{}
+	index := map[string]int{}
+
+	for _, g := range c.Games {
+		for _, key := range keys(g) {
+			if isUnknownTag(key) {
+				key = "unknown"
+			}
+
+			i, ok := index[key]
+			if !ok {
+				i = len(parts)
+				index[key] = i
+				parts = append(parts, Part{Name: key, Collection: NewCollection()})
+			}
+			parts[i].Collection.Add(g)
+		}
+	}
+
+	return parts
+}
+
+// WriteParts writes every part to a PGN file in dir, named after the part
+// with characters unsafe in file names replaced, and returns the paths
+// written.
+func WriteParts(dir string, parts []Part) ([]string, error) {
+	paths := []string{}
+	used := map[string]bool{}
+
+	for _, part := range parts {
+		name := partFileName(part.Name)
+		for n := 2; used[name]; n++ {
+			name = fmt.Sprintf("%s-%d", partFileName(part.Name), n)
+		}
+		used[name] = true
+
+		path := filepath.Join(dir, name+".pgn")
+		f, err := os.Create(path)
+		if err != nil {
+			return paths, err
+		}
+
+		err = part.Collection.Write(f)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return paths, err
+		}
+
+		paths = append(paths, path)
+	}
+
+	return paths, nil
+}
+
+// partFileName keeps letters, digits, dots and dashes, and turns runs of
+// anything else into an underscore.
+func partFileName(name string) string {
+	var sb strings.Builder
+	underscore := false
+
+	for _, r := range name {
+		if r == '.' || r == '-' || r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r > 0x7f {
+			sb.WriteRune(r)
+			underscore = false
+		} else if !underscore && sb.Len() > 0 {
+			sb.WriteByte('_')
+			underscore = true
+		}
+	}
+
+	name = strings.TrimRight(sb.String(), "_")
+	if name == "" || strings.Trim(name, ".") == "" {
+		return "unnamed"
+	}
+
+	return name
+}
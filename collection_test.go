@@ -0,0 +1,167 @@
+package pgn
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const collectionDatabase = `[Event "Open"]
+[Date "2023.05.10"]
+[Round "10"]
+[White "Carlsen, Magnus"]
+[Black "Nakamura, Hikaru"]
+[WhiteElo "2853"]
+[ECO "C60"]
+[Result "1-0"]
+
+1. e4 e5 2. Nf3 Nc6 3. Bb5 1-0
+
+[Event "Open"]
+[Date "2023.05.09"]
+[Round "2"]
+[White "Nakamura, Hikaru"]
+[Black "Caruana, Fabiano"]
+[WhiteElo "900"]
+[ECO "B90"]
+[Result "0-1"]
+
+1. e4 c5 0-1
+
+[Event "Blitz/Rapid"]
+[Date "????.??.??"]
+[Round "2.1"]
+[White "Caruana, Fabiano"]
+[Black "Carlsen, Magnus"]
+[Result "1/2-1/2"]
+
+1. d4 1/2-1/2
+`
+
+func readTestCollection(t *testing.T) *Collection {
+	t.Helper()
+
+	c, err := ReadCollection(strings.NewReader(collectionDatabase))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Len() != 3 {
+		t.Fatalf("got %d games, want 3", c.Len())
+	}
+
+	return c
+}
+
+func rounds(c *Collection) string {
+	var rounds []string
+	for _, g := range c.Games {
+		rounds = append(rounds, g.Round())
+	}
+
+	return strings.Join(rounds, " ")
+}
+
+func TestCollectionSort(t *testing.T) {
+	tests := []struct {
+		tag        string
+		descending bool
+		want       string
+	}{
+		{"Round", false, "2 2.1 10"},
+		{"Round", true, "10 2.1 2"},
+		{"Date", false, "2 10 2.1"},
+		{"Date", true, "10 2 2.1"},
+		{"WhiteElo", false, "2 10 2.1"},
+		{"WhiteElo", true, "10 2 2.1"},
+		{"White", false, "10 2.1 2"},
+	}
+
+	for _, tt := range tests {
+		c := readTestCollection(t)
+		c.Sort(tt.tag, tt.descending)
+		if got := rounds(c); got != tt.want {
+			t.Errorf("Sort(%q, %v) = %q, want %q", tt.tag, tt.descending, got, tt.want)
+		}
+	}
+}
+
+func TestCollectionSplit(t *testing.T) {
+	c := readTestCollection(t)
+
+	names := func(parts []Part) string {
+		var names []string
+		for _, part := range parts {
+			names = append(names, part.Name+":"+rounds(part.Collection))
+		}
+		return strings.Join(names, ",")
+	}
+
+	tests := []struct {
+		name  string
+		parts []Part
+		want  string
+	}{
+		{"event", c.SplitByEvent(), "Open:10 2,Blitz/Rapid:2.1"},
+		{"eco", c.SplitByECO(), "C60:10,B90:2,unknown:2.1"},
+		{"player", c.SplitByPlayer(), "Carlsen, Magnus:10 2.1,Nakamura, Hikaru:10 2,Caruana, Fabiano:2 2.1"},
+		{"size", c.SplitBySize(2), "1:10 2,2:2.1"},
+	}
+
+	for _, tt := range tests {
+		if got := names(tt.parts); got != tt.want {
+			t.Errorf("split by %s = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCollectionMerge(t *testing.T) {
+	a, b := readTestCollection(t), readTestCollection(t)
+
+	merged := Merge(a, b)
+	if merged.Len() != 6 {
+		t.Fatalf("got %d games, want 6", merged.Len())
+	}
+
+	if groups := merged.Dedupe(DefaultDuplicateOptions); len(groups) != 3 || merged.Len() != 3 {
+		t.Errorf("got %d groups and %d games, want 3 and 3", len(groups), merged.Len())
+	}
+
+	var sb strings.Builder
+	if err := merged.Write(&sb); err != nil {
+		t.Fatal(err)
+	}
+	again, err := ReadCollection(strings.NewReader(sb.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rounds(again) != rounds(a) {
+		t.Errorf("round trip gave rounds %q, want %q", rounds(again), rounds(a))
+	}
+}
+
+func TestWriteParts(t *testing.T) {
+	dir := t.TempDir()
+	c := readTestCollection(t)
+
+	parts := append(c.SplitByEvent(), Part{Name: "Open", Collection: NewCollection()})
+	paths, err := WriteParts(dir, parts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"Open.pgn", "Blitz_Rapid.pgn", "Open-2.pgn"}
+	for i, path := range paths {
+		if filepath.Base(path) != want[i] {
+			t.Errorf("path %d = %s, want %s", i, filepath.Base(path), want[i])
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "Blitz_Rapid.pgn"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `[Round "2.1"]`) {
+		t.Errorf("part file missing game:\n%s", data)
+	}
+}
@@ -0,0 +1,162 @@
+package pgn
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Command is an embedded command annotation such as [%clk 0:03:12], as
+// used by lichess, chess.com and ChessBase inside move comments.
+type Command struct {
+	Name  string
+	Value string
+}
+
+func (c Command) String() string {
+	return fmt.Sprintf("[%%%s %s]", c.Name, c.Value)
+}
+
+var commandPattern = regexp.MustCompile(`\[%(\w+)\s*([^\]]*)\]`)
+
+// ParseCommands returns the command annotations embedded in a comment, in
+// the order they appear.
+func ParseCommands(comment string) []Command {
+	commands := []Command{}
+
+	for _, m := range commandPattern.FindAllStringSubmatch(comment, -1) {
+		commands = append(commands, Command{Name: m[1], Value: strings.TrimSpace(m[2])})
+	}
+
+	return commands
+}
+
+// StripCommands returns the comment without its command annotations.
+func StripCommands(comment string) string {
+	return strings.Join(strings.Fields(commandPattern.ReplaceAllString(comment, " ")), " ")
+}
+
+// ParseClock parses a clock value written as H:MM:SS, M:SS or SS. Seconds
+// may carry a fraction, as in chess.com's "0:02:59.9".
+func ParseClock(s string) (time.Duration, error) {
+	parts := strings.Split(strings.TrimSpace(s), ":")
+	if len(parts) > 3 || parts[0] == "" {
+		return 0, fmt.Errorf("invalid clock value %q", s)
+	}
+
+	seconds, err := strconv.ParseFloat(parts[len(parts)-1], 64)
+	if err != nil || seconds < 0 {
+		return 0, fmt.Errorf("invalid clock value %q", s)
+	}
+
+	total := time.Duration(seconds * float64(time.Second))
+
+	unit := time.Minute
+	for i := len(parts) - 2; i >= 0; i-- {
+		n, err := strconv.Atoi(parts[i])
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid clock value %q", s)
+		}
+
+		total += time.Duration(n) * unit
+		unit *= 60
+	}
+
+	return total, nil
+}
+
+// FormatClock writes d as H:MM:SS, the form used by %clk and %emt. Tenths of
+// a second are kept when present.
+func FormatClock(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+
+	tenths := (d % time.Second) / (100 * time.Millisecond)
+	d -= d % time.Second
+
+	h := int(d / time.Hour)
+	m := int(d % time.Hour / time.Minute)
+	s := int(d % time.Minute / time.Second)
+
+	if tenths > 0 {
+		return fmt.Sprintf("%d:%02d:%02d.%d", h, m, s, tenths)
+	}
+
+	return fmt.Sprintf("%d:%02d:%02d", h, m, s)
+}
+
+// setCommand sets the named command in comments, replacing an existing one
+// in place or adding it to the first comment.
+func setCommand(comments []string, name, value string) []string {
+	cmd := Command{Name: name, Value: value}.String()
+
+	for i, c := range comments {
+		replaced := false
+		comments[i] = commandPattern.ReplaceAllStringFunc(c, func(m string) string {
+			if commandPattern.FindStringSubmatch(m)[1] != name || replaced {
+				return m
+			}
+			replaced = true
+			return cmd
+		})
+
+		if replaced {
+			return comments
+		}
+	}
+
+	if len(comments) == 0 {
+		return []string{cmd}
+	}
+
+	comments[0] = strings.TrimSpace(cmd + " " + comments[0])
+	return comments
+}
+
+// removeCommand removes the named command from comments, dropping comments
+// that are left empty.
+func removeCommand(comments []string, name string) []string {
+	kept := []string{}
+
+	for _, c := range comments {
+		stripped := commandPattern.ReplaceAllStringFunc(c, func(m string) string {
+			if commandPattern.FindStringSubmatch(m)[1] == name {
+				return ""
+			}
+			return m
+		})
+
+		if stripped = strings.Join(strings.Fields(stripped), " "); stripped != "" {
+			kept = append(kept, stripped)
+		}
+	}
+
+	return kept
+}
+
+// SetCommand sets a command annotation such as [%clk 0:03:00] in the
+// comments of the given ply, counted from 1.
+func (g *Game) SetCommand(ply int, name, value string) error {
+	comments, err := g.plyComments(ply)
+	if err != nil {
+		return err
+	}
+
+	*comments = setCommand(*comments, name, value)
+	return nil
+}
+
+// RemoveCommand removes a command annotation from the comments of the given
+// ply.
+func (g *Game) RemoveCommand(ply int, name string) error {
+	comments, err := g.plyComments(ply)
+	if err != nil {
+		return err
+	}
+
+	*comments = removeCommand(*comments, name)
+	return nil
+}
@@ -0,0 +1,200 @@
+package pgn
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+const clockGame = `[Event "Live Chess"]
+[Result "0-1"]
+[TimeControl "180"]
+
+{Casual game} 1. e4 {[%clk 0:03:00]} 1... e5 {[%clk 0:02:58.4]} 2. Nf3?! {[%clk 0:02:55] [%emt 0:00:05] Slow start} 2... Nc6 {[%clk 0:02:50]} 3. Bc4 {[%clk 0:02:41]} 3... Nd4 {[%clk 0:02:49]} 0-1`
+
+func TestParseCommands(t *testing.T) {
+	comment := "[%clk 0:02:55] [%cal Ge2e4,Rd8d1] Slow start [%emt 0:00:05]"
+
+	expected := []Command{
+		{Name: "clk", Value: "0:02:55"},
+		{Name: "cal", Value: "Ge2e4,Rd8d1"},
+		{Name: "emt", Value: "0:00:05"},
+	}
+
+	if got := ParseCommands(comment); !reflect.DeepEqual(got, expected) {
+		t.Errorf("ParseCommands() = %v, want %v", got, expected)
+	}
+
+	if got := StripCommands(comment); got != "Slow start" {
+		t.Errorf("StripCommands() = %q, want %q", got, "Slow start")
+	}
+}
+
+func TestParseClock(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected time.Duration
+	}{
+		{"0:03:12", 3*time.Minute + 12*time.Second},
+		{"1:30:00", 90 * time.Minute},
+		{"0:02:59.9", 2*time.Minute + 59900*time.Millisecond},
+		{"4:05", 4*time.Minute + 5*time.Second},
+		{"42", 42 * time.Second},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseClock(tt.input)
+		if err != nil {
+			t.Errorf("ParseClock(%q) error: %v", tt.input, err)
+			continue
+		}
+		if got != tt.expected {
+			t.Errorf("ParseClock(%q) = %v, want %v", tt.input, got, tt.expected)
+		}
+	}
+
+	for _, input := range []string{"", "a:00", "1:2:3:4", "-5"} {
+		if _, err := ParseClock(input); err == nil {
+			t.Errorf("ParseClock(%q) expected error", input)
+		}
+	}
+
+	if got := FormatClock(2*time.Minute + 58400*time.Millisecond); got != "0:02:58.4" {
+		t.Errorf("FormatClock() = %q, want %q", got, "0:02:58.4")
+	}
+}
+
+func TestPlyComments(t *testing.T) {
+	game, err := New(clockGame)
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+
+	if got := game.Comments(); !reflect.DeepEqual(got, []string{"Casual game"}) {
+		t.Errorf("Comments() = %v, want %v", got, []string{"Casual game"})
+	}
+
+	plies := game.Plies()
+	if len(plies) != 6 {
+		t.Fatalf("len(Plies()) = %d, want 6", len(plies))
+	}
+
+	third := plies[2]
+	if third.SAN != "Nf3" || third.Color != White || third.MoveNumber != 2 {
+		t.Errorf("Plies()[2] = %+v, want white's Nf3 on move 2", third)
+	}
+	if !reflect.DeepEqual(third.Annotations, []string{"6"}) {
+		t.Errorf("Plies()[2].Annotations = %v, want [6]", third.Annotations)
+	}
+
+	if clock, ok := third.Clock(); !ok || clock != 2*time.Minute+55*time.Second {
+		t.Errorf("Clock() = %v, %v, want 2m55s", clock, ok)
+	}
+	if emt, ok := third.ElapsedTime(); !ok || emt != 5*time.Second {
+		t.Errorf("ElapsedTime() = %v, %v, want 5s", emt, ok)
+	}
+	if _, ok := plies[0].ElapsedTime(); ok {
+		t.Errorf("ElapsedTime() of the first ply expected no value")
+	}
+}
+
+func TestTimeUsage(t *testing.T) {
+	game, err := New(clockGame)
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+
+	black := game.TimeUsage(Black)
+	if len(black) != 3 {
+		t.Fatalf("len(TimeUsage(Black)) = %d, want 3", len(black))
+	}
+
+	if black[0].HasElapsed {
+		t.Errorf("first black move expected no elapsed time")
+	}
+
+	expected := []time.Duration{8400 * time.Millisecond, time.Second}
+	for i, want := range expected {
+		u := black[i+1]
+		if !u.HasElapsed || u.Elapsed != want {
+			t.Errorf("TimeUsage(Black)[%d].Elapsed = %v, %v, want %v", i+1, u.Elapsed, u.HasElapsed, want)
+		}
+	}
+
+	white := game.TimeUsage(White)
+	if white[1].Elapsed != 5*time.Second || white[2].Elapsed != 14*time.Second {
+		t.Errorf("TimeUsage(White) elapsed = %v, %v, want 5s, 14s", white[1].Elapsed, white[2].Elapsed)
+	}
+}
+
+func TestParseEvaluation(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Evaluation
+		output   string
+	}{
+		{"0.35", Evaluation{Centipawns: 35}, "0.35"},
+		{"-1.2", Evaluation{Centipawns: -120}, "-1.2"},
+		{"#-3", Evaluation{Mate: -3}, "#-3"},
+		{"#2", Evaluation{Mate: 2}, "#2"},
+		{"0.17,20", Evaluation{Centipawns: 17, Depth: 20}, "0.17,20"},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseEvaluation(tt.input)
+		if err != nil {
+			t.Errorf("ParseEvaluation(%q) error: %v", tt.input, err)
+			continue
+		}
+		if got != tt.expected {
+			t.Errorf("ParseEvaluation(%q) = %+v, want %+v", tt.input, got, tt.expected)
+		}
+		if got.String() != tt.output {
+			t.Errorf("Evaluation.String() = %q, want %q", got.String(), tt.output)
+		}
+	}
+
+	for _, input := range []string{"", "#0", "abc", "0.3,x"} {
+		if _, err := ParseEvaluation(input); err == nil {
+			t.Errorf("ParseEvaluation(%q) expected error", input)
+		}
+	}
+}
+
+func TestSetEvaluation(t *testing.T) {
+	game, err := New(`[Result "*"]
+
+1. e4 {[%eval 0.2] [%clk 0:03:00]} e5 {good} 2. Nf3 *`)
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+
+	if e, ok := game.Plies()[0].Evaluation(); !ok || e.Centipawns != 20 {
+		t.Errorf("Evaluation() = %+v, %v, want 0.2", e, ok)
+	}
+
+	if err := game.SetEvaluation(1, Evaluation{Centipawns: 31, Depth: 18}); err != nil {
+		t.Fatalf("SetEvaluation() error: %v", err)
+	}
+	if err := game.SetEvaluation(2, Evaluation{Mate: -4}); err != nil {
+		t.Fatalf("SetEvaluation() error: %v", err)
+	}
+	if err := game.SetEvaluation(3, Evaluation{}); err != nil {
+		t.Fatalf("SetEvaluation() error: %v", err)
+	}
+	if err := game.SetEvaluation(4, Evaluation{}); err == nil {
+		t.Errorf("SetEvaluation() past the last ply expected error")
+	}
+
+	expected := "[Result \"*\"]\n\n1. e4 {[%eval 0.31,18] [%clk 0:03:00]} 1... e5 {[%eval #-4] good} 2. Nf3\n{[%eval 0]} *\n"
+	if got := game.PGN(); got != expected {
+		t.Errorf("PGN() = %q, want %q", got, expected)
+	}
+
+	if err := game.RemoveCommand(3, "eval"); err != nil {
+		t.Fatalf("RemoveCommand() error: %v", err)
+	}
+	if comments := game.Plies()[2].Comments; len(comments) != 0 {
+		t.Errorf("comments after RemoveCommand() = %v, want none", comments)
+	}
+}
@@ -4,3 +4,5 @@ const MAX_CHARACTERS_IN_LINE = 255
 const MOVE = "MOVE"
 const TAG_PAIR = "TAG_PAIR"
 const TERMINATION = "TERMINATION"
+const COMMENT_TEXT = "COMMENT_TEXT"
+const VARIATION = "VARIATION"
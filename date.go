@@ -0,0 +1,98 @@
+package pgn
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Date is a date from a PGN tag such as "2024.03.??". Unknown parts are 0.
+type Date struct {
+	Year  int
+	Month int
+	Day   int
+}
+
+// ParseDate parses a PGN date. It accepts "YYYY.MM.DD" with "??" for unknown
+// parts, a year and month or a year alone, and "-" or "/" as separators.
+func ParseDate(s string) (Date, error) {
+	fields := strings.FieldsFunc(strings.TrimSpace(s), func(r rune) bool {
+		return r == '.' || r == '-' || r == '/'
+	})
+	if len(fields) == 0 || len(fields) > 3 {
+		return Date{}, fmt.Errorf("invalid date %q", s)
+	}
+
+	parts := [3]int{}
+	limits := [3]int{9999, 12, 31}
+	for i, field := range fields {
+		if strings.Trim(field, "?") == "" {
+			continue
+		}
+
+		n, err := strconv.Atoi(field)
+		if err != nil || n < 0 || n > limits[i] {
+			return Date{}, fmt.Errorf("invalid date %q", s)
+		}
+		parts[i] = n
+	}
+
+	return Date{Year: parts[0], Month: parts[1], Day: parts[2]}, nil
+}
+
+// IsZero reports whether the year is unknown.
+func (d Date) IsZero() bool {
+	return d.Year == 0
+}
+
+// Compare returns -1, 0 or 1 as d is before, equal to or after o. Unknown
+// parts sort before known ones.
+func (d Date) Compare(o Date) int {
+	for _, pair := range [][2]int{{d.Year, o.Year}, {d.Month, o.Month}, {d.Day, o.Day}} {
+		if pair[0] < pair[1] {
+			return -1
+		}
+		if pair[0] > pair[1] {
+			return 1
+		}
+	}
+
+	return 0
+}
+
+// String writes the date in PGN form.
+func (d Date) String() string {
+	part := func(n, width int) string {
+		if n == 0 {
+			return strings.Repeat("?", width)
+		}
+		return fmt.Sprintf("%0*d", width, n)
+	}
+
+	return part(d.Year, 4) + "." + part(d.Month, 2) + "." + part(d.Day, 2)
+}
+
+// ParsedDate returns the Date tag as a Date.
+func (g *Game) ParsedDate() (Date, error) {
+	return ParseDate(g.Date())
+}
+
+// WhiteElo returns the WhiteElo tag as a number. It is false when the tag is
+// missing or not a rating, such as "-".
+func (g *Game) WhiteElo() (int, bool) {
+	return parseElo(g.tags["WhiteElo"])
+}
+
+// BlackElo returns the BlackElo tag as a number.
+func (g *Game) BlackElo() (int, bool) {
+	return parseElo(g.tags["BlackElo"])
+}
+
+func parseElo(s string) (int, bool) {
+	elo, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil || elo <= 0 {
+		return 0, false
+	}
+
+	return elo, true
+}
@@ -0,0 +1,58 @@
+package pgn
+
+import "testing"
+
+func TestParseDate(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Date
+		valid    bool
+	}{
+		{"2024.03.15", Date{2024, 3, 15}, true},
+		{"2024.03.??", Date{2024, 3, 0}, true},
+		{"????.??.??", Date{}, true},
+		{"2024", Date{2024, 0, 0}, true},
+		{"2024-03", Date{2024, 3, 0}, true},
+		{"2024/03/15", Date{2024, 3, 15}, true},
+		{"2024.13.01", Date{}, false},
+		{"March 2024", Date{}, false},
+		{"", Date{}, false},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseDate(tt.input)
+		if (err == nil) != tt.valid {
+			t.Errorf("ParseDate(%q) error = %v, want valid %v", tt.input, err, tt.valid)
+			continue
+		}
+		if got != tt.expected {
+			t.Errorf("ParseDate(%q) = %+v, want %+v", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestDateCompare(t *testing.T) {
+	a, b, c := Date{2024, 3, 0}, Date{2024, 3, 15}, Date{2023, 12, 31}
+
+	if a.Compare(b) != -1 || b.Compare(a) != 1 || c.Compare(a) != -1 || b.Compare(b) != 0 {
+		t.Errorf("Compare() does not order %v < %v < %v", c, a, b)
+	}
+
+	if a.String() != "2024.03.??" || (Date{}).String() != "????.??.??" {
+		t.Errorf("String() = %q, %q", a.String(), Date{}.String())
+	}
+}
+
+func TestElo(t *testing.T) {
+	game, err := New("[WhiteElo \"2750\"]\n[BlackElo \"-\"]\n[Result \"*\"]\n\n*")
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+
+	if elo, ok := game.WhiteElo(); !ok || elo != 2750 {
+		t.Errorf("WhiteElo() = %d, %v, want 2750", elo, ok)
+	}
+	if _, ok := game.BlackElo(); ok {
+		t.Errorf("BlackElo() of \"-\" is known")
+	}
+}
@@ -0,0 +1,315 @@
+package pgn
+
+import (
+	"image/color"
+	"math"
+)
+
+// squareSize is the width of a square in diagram units, the size piece sets
+// are drawn at. A diagram is eight squares wide.
+const squareSize = 45
+
+// Theme holds the colors of a board diagram. Translucent colors are drawn
+// over what is below them.
+type Theme struct {
+	Name        string
+	Light       color.RGBA
+	Dark        color.RGBA
+	LastMove    color.RGBA
+	WhitePiece  color.RGBA
+	BlackPiece  color.RGBA
+	Outline     color.RGBA
+	Arrows      map[MarkColor]color.RGBA
+	Coordinates [2]color.RGBA // on light and on dark squares
+}
+
+var lichessMarks = map[MarkColor]color.RGBA{
+	MarkGreen:  {0x15, 0x78, 0x1b, 0xcc},
+	MarkRed:    {0x88, 0x20, 0x20, 0xcc},
+	MarkYellow: {0xe6, 0x8f, 0x00, 0xcc},
+	MarkBlue:   {0x00, 0x30, 0x88, 0xcc},
+}
+
+var (
+	BrownTheme = &Theme{
+		Name:        "brown",
+		Light:       color.RGBA{0xf0, 0xd9, 0xb5, 0xff},
+		Dark:        color.RGBA{0xb5, 0x88, 0x63, 0xff},
+		LastMove:    color.RGBA{0x9b, 0xc7, 0x00, 0x69},
+		WhitePiece:  color.RGBA{0xff, 0xff, 0xff, 0xff},
+		BlackPiece:  color.RGBA{0x22, 0x22, 0x22, 0xff},
+		Outline:     color.RGBA{0x00, 0x00, 0x00, 0xff},
+		Arrows:      lichessMarks,
+		Coordinates: [2]color.RGBA{{0xb5, 0x88, 0x63, 0xff}, {0xf0, 0xd9, 0xb5, 0xff}},
+	}
+	BlueTheme = &Theme{
+		Name:        "blue",
+		Light:       color.RGBA{0xde, 0xe3, 0xe6, 0xff},
+		Dark:        color.RGBA{0x8c, 0xa2, 0xad, 0xff},
+		LastMove:    color.RGBA{0x9b, 0xc7, 0x00, 0x69},
+		WhitePiece:  color.RGBA{0xff, 0xff, 0xff, 0xff},
+		BlackPiece:  color.RGBA{0x22, 0x22, 0x22, 0xff},
+		Outline:     color.RGBA{0x00, 0x00, 0x00, 0xff},
+		Arrows:      lichessMarks,
+		Coordinates: [2]color.RGBA{{0x8c, 0xa2, 0xad, 0xff}, {0xde, 0xe3, 0xe6, 0xff}},
+	}
+	GreenTheme = &Theme{
+		Name:        "green",
+		Light:       color.RGBA{0xee, 0xee, 0xd2, 0xff},
+		Dark:        color.RGBA{0x76, 0x96, 0x56, 0xff},
+		LastMove:    color.RGBA{0xff, 0xff, 0x33, 0x80},
+		WhitePiece:  color.RGBA{0xf9, 0xf9, 0xf9, 0xff},
+		BlackPiece:  color.RGBA{0x56, 0x53, 0x52, 0xff},
+		Outline:     color.RGBA{0x1f, 0x1f, 0x1f, 0xff},
+		Arrows:      lichessMarks,
+		Coordinates: [2]color.RGBA{{0x76, 0x96, 0x56, 0xff}, {0xee, 0xee, 0xd2, 0xff}},
+	}
+)
+
+// DiagramOptions configures graphical board diagrams.
+type DiagramOptions struct {
+	// Size is the width and height of the diagram in pixels.
+	Size int
+	// Theme defaults to BrownTheme and Pieces to ClassicPieces.
+	Theme  *Theme
+	Pieces *PieceSet
+	// Coordinates labels the files and ranks along the edge of the board.
+	Coordinates bool
+	// Flip draws the board from black's side.
+	Flip bool
+	// LastMove shades the squares of the move that reached the position.
+	LastMove bool
+	// Marks draws the %cal arrows and %csl highlights of the ply.
+	Marks bool
+}
+
+var DefaultDiagramOptions = DiagramOptions{
+	Size:        360,
+	Theme:       BrownTheme,
+	Pieces:      ClassicPieces,
+	Coordinates: true,
+	LastMove:    true,
+	Marks:       true,
+}
+
+func (opts DiagramOptions) withDefaults() DiagramOptions {
+	if opts.Size <= 0 {
+		opts.Size = DefaultDiagramOptions.Size
+	}
+	if opts.Theme == nil {
+		opts.Theme = BrownTheme
+	}
+	if opts.Pieces == nil {
+		opts.Pieces = ClassicPieces
+	}
+
+	return opts
+}
+
+// shape is a filled polygon of a diagram. Several contours make holes
+// using the even-odd rule.
+type shape struct {
+	contours    [][]point
+	fill        color.RGBA
+	stroke      color.RGBA
+	strokeWidth float64
+}
+
+// label is a line of text with its baseline starting at x, y.
+type label struct {
+	x, y  float64
+	size  float64
+	text  string
+	color color.RGBA
+}
+
+// diagram is a board drawing independent of the output format, in diagram
+// units. Shapes are drawn in order, followed by the labels.
+type diagram struct {
+	shapes []shape
+	labels []label
+}
+
+// diagramMarks are the annotations drawn over a position.
+type diagramMarks struct {
+	from, to   Square
+	arrows     []Arrow
+	highlights []Highlight
+}
+
+// diagram draws the position after the given ply with its last move and
+// marks as selected by opts.
+func (g *Game) diagram(ply int, opts DiagramOptions) (*diagram, error) {
+	pos, from, to, err := g.renderState(ply)
+	if err != nil {
+		return nil, err
+	}
+
+	marks := diagramMarks{from: NoSquare, to: NoSquare}
+	if opts.LastMove {
+		marks.from, marks.to = from, to
+	}
+	if opts.Marks {
+		marks.arrows, marks.highlights = g.Marks(ply)
+	}
+
+	return pos.diagram(opts, marks), nil
+}
+
+func (pos *Position) diagram(opts DiagramOptions, marks diagramMarks) *diagram {
+	opts = opts.withDefaults()
+	theme := opts.Theme
+	d := &diagram{}
+
+	for sq := Square(0); sq < 64; sq++ {
+		fill := theme.Dark
+		if isLightSquare(sq) {
+			fill = theme.Light
+		}
+		d.shapes = append(d.shapes, shape{contours: [][]point{squareRect(sq, opts.Flip)}, fill: fill})
+	}
+
+	for _, sq := range []Square{marks.from, marks.to} {
+		if sq != NoSquare {
+			d.shapes = append(d.shapes, shape{contours: [][]point{squareRect(sq, opts.Flip)}, fill: theme.LastMove})
+		}
+	}
+
+	for _, h := range marks.highlights {
+		c := squareCenter(h.Square, opts.Flip)
+		ring := [][]point{
+			circle(c.x, c.y, squareSize*0.47, 32),
+			circle(c.x, c.y, squareSize*0.40, 32),
+		}
+		d.shapes = append(d.shapes, shape{contours: ring, fill: theme.Arrows[h.Color]})
+	}
+
+	if opts.Coordinates {
+		d.labels = coordinateLabels(theme, opts.Flip)
+	}
+
+	for sq := Square(0); sq < 64; sq++ {
+		p := pos.board[sq]
+		if p.IsEmpty() {
+			continue
+		}
+
+		fill := theme.WhitePiece
+		if p.Color == Black {
+			fill = theme.BlackPiece
+		}
+
+		corner := squareRect(sq, opts.Flip)[0]
+		for _, polygon := range opts.Pieces.shapes[p.Type] {
+			moved := make([]point, len(polygon))
+			for i, pt := range polygon {
+				moved[i] = point{corner.x + pt.x, corner.y + pt.y}
+			}
+			d.shapes = append(d.shapes, shape{
+				contours:    [][]point{moved},
+				fill:        fill,
+				stroke:      theme.Outline,
+				strokeWidth: opts.Pieces.outline,
+			})
+		}
+	}
+
+	for _, a := range marks.arrows {
+		if a.From == a.To {
+			continue
+		}
+		from, to := squareCenter(a.From, opts.Flip), squareCenter(a.To, opts.Flip)
+		d.shapes = append(d.shapes, shape{contours: [][]point{arrowPolygon(from, to)}, fill: theme.Arrows[a.Color]})
+	}
+
+	return d
+}
+
+func isLightSquare(sq Square) bool {
+	return (sq.File()+sq.Rank())%2 == 1
+}
+
+// squareRect returns the corners of a square, starting at the top left.
+func squareRect(sq Square, flip bool) []point {
+	col, row := sq.File(), 7-sq.Rank()
+	if flip {
+		col, row = 7-col, 7-row
+	}
+
+	x, y := float64(col*squareSize), float64(row*squareSize)
+	return rect(x, y, x+squareSize, y+squareSize)
+}
+
+func squareCenter(sq Square, flip bool) point {
+	corner := squareRect(sq, flip)[0]
+	return point{corner.x + squareSize/2.0, corner.y + squareSize/2.0}
+}
+
+// arrowPolygon returns an arrow from the center of one square with its tip
+// on the center of another.
+func arrowPolygon(from, to point) []point {
+	const shaft, head, headLength = 4.5, 11.0, 18.0
+
+	dx, dy := to.x-from.x, to.y-from.y
+	length := math.Hypot(dx, dy)
+	ux, uy := dx/length, dy/length
+	nx, ny := -uy, ux
+
+	base := point{to.x - ux*headLength, to.y - uy*headLength}
+	side := func(p point, w float64) point {
+		return point{p.x + nx*w, p.y + ny*w}
+	}
+
+	return []point{
+		side(from, shaft), side(base, shaft), side(base, head), to,
+		side(base, -head), side(base, -shaft), side(from, -shaft),
+	}
+}
+
+// coordinateLabels puts the file letters in the bottom right corner of the
+// bottom squares and the rank numbers in the top left corner of the left
+// squares, colored to contrast with the square.
+func coordinateLabels(theme *Theme, flip bool) []label {
+	const size = 10.0
+	labels := []label{}
+
+	for i := 0; i < 8; i++ {
+		file, rank := i, 0
+		if flip {
+			file, rank = 7-i, 7
+		}
+		sq := newSquare(file, rank)
+		corner := squareRect(sq, flip)[2]
+		labels = append(labels, label{
+			x:     corner.x - size*0.6 - 2,
+			y:     corner.y - 2,
+			size:  size,
+			text:  string(rune('a' + file)),
+			color: coordinateColor(theme, sq),
+		})
+
+		file, rank = 0, 7-i
+		if flip {
+			file, rank = 7, i
+		}
+		sq = newSquare(file, rank)
+		corner = squareRect(sq, flip)[0]
+		labels = append(labels, label{
+			x:     corner.x + 2,
+			y:     corner.y + size + 1,
+			size:  size,
+			text:  string(rune('1' + rank)),
+			color: coordinateColor(theme, sq),
+		})
+	}
+
+	return labels
+}
+
+func coordinateColor(theme *Theme, sq Square) color.RGBA {
+	if isLightSquare(sq) {
+		return theme.Coordinates[0]
+	}
+
+	return theme.Coordinates[1]
+}
@@ -0,0 +1,263 @@
+package pgn
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DuplicateOptions configures duplicate detection.
+type DuplicateOptions struct {
+	// DateTolerance is how many days apart the dates of two copies of a game
+	// may be.
+	DateTolerance int
+	// MovesOnly treats games with the same moves as duplicates whatever
+	// their tags.
+	MovesOnly bool
+}
+
+var DefaultDuplicateOptions = DuplicateOptions{DateTolerance: 3}
+
+// DuplicateGroup is a set of copies of the same game, by their index in the
+// searched games. Keep is the index of the most complete copy.
+type DuplicateGroup struct {
+	Games []int
+	Keep  int
+}
+
+// MoveHash returns a hash of the positions of the game's main line, so that
+// copies that spell their moves or FEN differently, as in "Ngf3" and "Nf3"
+// or "0-0" and "O-O", hash alike. Games that cannot be replayed hash their
+// FEN tag and moves as written, ignoring check marks and suffix annotations.
+func (g *Game) MoveHash() uint64 {
+	h := fnv.New64a()
+
+	if positions, err := g.Positions(); err == nil {
+		for _, pos := range positions {
+			binary.Write(h, binary.LittleEndian, pos.Hash())
+		}
+		return h.Sum64()
+	}
+
+	h.Write([]byte(g.GetTag("FEN")))
+	for _, p := range g.Plies() {
+		h.Write([]byte{' '})
+		h.Write([]byte(stripSANSuffix(p.SAN)))
+	}
+
+	return h.Sum64()
+}
+
+// FindDuplicates groups games that have the same moves and whose players,
+// dates and results match loosely: names are compared by surname and first
+// initial, dates may differ by the tolerance or be partly unknown, and an
+// unknown result matches any. Groups are ordered by their first game.
+func FindDuplicates(games []*Game, opts DuplicateOptions) []DuplicateGroup {
+	byHash := map[uint64][]int{}
+	hashes := []uint64{}
+	for i, g := range games {
+		h := g.MoveHash()
+		if _, ok := byHash[h]; !ok {
+			hashes = append(hashes, h)
+		}
+		byHash[h] = append(byHash[h], i)
+	}
+
+	groups := []DuplicateGroup{}
+	for _, h := range hashes {
+		candidates := byHash[h]
+		if len(candidates) < 2 {
+			continue
+		}
+
+		// A copy joins the first cluster whose every game it matches, so that
+		// a vague copy cannot chain two different games together.
+		clusters := [][]int{}
+		for _, i := range candidates {
+			joined := false
+			for c, cluster := range clusters {
+				matches := true
+				for _, j := range cluster {
+					if !opts.MovesOnly && !sameGame(games[i], games[j], opts) {
+						matches = false
+						break
+					}
+				}
+				if matches {
+					clusters[c] = append(cluster, i)
+					joined = true
+					break
+				}
+			}
+			if !joined {
+				clusters = append(clusters, []int{i})
+			}
+		}
+
+		for _, cluster := range clusters {
+			if len(cluster) < 2 {
+				continue
+			}
+
+			keep := cluster[0]
+			for _, i := range cluster[1:] {
+				if completeness(games[i]) > completeness(games[keep]) {
+					keep = i
+				}
+			}
+			groups = append(groups, DuplicateGroup{Games: cluster, Keep: keep})
+		}
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].Games[0] < groups[j].Games[0]
+	})
+
+	return groups
+}
+
+// Dedupe returns the games without their duplicates, and the duplicate
+// groups found. The most complete copy of each game takes the place of its
+// first copy.
+func Dedupe(games []*Game, opts DuplicateOptions) ([]*Game, []DuplicateGroup) {
+	groups := FindDuplicates(games, opts)
+
+	replace := map[int]int{}
+	drop := map[int]bool{}
+	for _, group := range groups {
+		replace[group.Games[0]] = group.Keep
+		for _, i := range group.Games[1:] {
+			drop[i] = true
+		}
+	}
+
+	kept := []*Game{}
+	for i, g := range games {
+		if keep, ok := replace[i]; ok {
+			kept = append(kept, games[keep])
+		} else if !drop[i] {
+			kept = append(kept, g)
+		}
+	}
+
+	return kept, groups
+}
+
+func sameGame(a, b *Game, opts DuplicateOptions) bool {
+	if !SamePlayer(a.White(), b.White()) || !SamePlayer(a.Black(), b.Black()) {
+		return false
+	}
+
+	if ra, rb := a.Result(), b.Result(); ra != rb && ra != "*" && rb != "*" && ra != "" && rb != "" {
+		return false
+	}
+
+	da, errA := a.ParsedDate()
+	db, errB := b.ParsedDate()
+	if errA != nil || errB != nil {
+		return true
+	}
+
+	return closeDates(da, db, opts.DateTolerance)
+}
+
+// closeDates reports whether two dates are at most tolerance days apart.
+// Dates that are partly unknown only need to agree on their known parts.
+func closeDates(a, b Date, tolerance int) bool {
+	if a.IsZero() || b.IsZero() {
+		return true
+	}
+
+	if a.Month == 0 || a.Day == 0 || b.Month == 0 || b.Day == 0 {
+		if a.Year != b.Year {
+			return false
+		}
+		return a.Month == 0 || b.Month == 0 || a.Month == b.Month
+	}
+
+	ta := time.Date(a.Year, time.Month(a.Month), a.Day, 0, 0, 0, 0, time.UTC)
+	tb := time.Date(b.Year, time.Month(b.Month), b.Day, 0, 0, 0, 0, time.UTC)
+	days := ta.Sub(tb).Hours() / 24
+	if days < 0 {
+		days = -days
+	}
+
+	return days <= float64(tolerance)
+}
+
+// completeness scores how much a copy of a game holds: known tags,
+// comments, NAGs, variations and a final result.
+func completeness(g *Game) int {
+	score := 0
+	for _, value := range g.tags {
+		if value != "" && value != "-" && !strings.Contains(value, "?") {
+			score++
+		}
+	}
+
+	for _, p := range g.Plies() {
+		score += len(p.Comments) + len(p.Annotations) + len(p.Variations)
+	}
+
+	if r := g.Result(); r != "" && r != "*" {
+		score++
+	}
+
+	return score
+}
+
+var nameFolder = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ä", "a", "ã", "a", "å", "a",
+	"é", "e", "è", "e", "ê", "e", "ë", "e",
+	"í", "i", "ì", "i", "î", "i", "ï", "i",
+	"ó", "o", "ò", "o", "ô", "o", "ö", "o", "õ", "o", "ø", "o",
+	"ú", "u", "ù", "u", "û", "u", "ü", "u",
+	"ý", "y", "ñ", "n", "ç", "c", "š", "s", "ž", "z", "č", "c", "ř", "r", "ł", "l", "ß", "ss",
+	".", " ", "_", " ",
+)
+
+// playerName splits a name written "Surname, Given" or "Given Surname" into
+// a folded surname and first initial.
+func playerName(name string) (string, string) {
+	name = nameFolder.Replace(strings.ToLower(strings.TrimSpace(name)))
+
+	var surname string
+	var given []string
+	if before, after, found := strings.Cut(name, ","); found {
+		surname, given = before, strings.Fields(after)
+	} else {
+		fields := strings.Fields(name)
+		if len(fields) == 0 {
+			return "", ""
+		}
+		surname, given = fields[len(fields)-1], fields[:len(fields)-1]
+	}
+
+	surname = strings.Join(strings.Fields(surname), "")
+	if len(given) == 0 {
+		return surname, ""
+	}
+
+	return surname, string([]rune(given[0])[:1])
+}
+
+// SamePlayer reports whether two spellings of a player's name could be the
+// same person, such as "Carlsen, Magnus", "Magnus Carlsen" and "Carlsen,M".
+// Surnames must match, ignoring case and common accents, and first
+// initials must match when both are given.
+func SamePlayer(a, b string) bool {
+	if strings.EqualFold(strings.TrimSpace(a), strings.TrimSpace(b)) {
+		return true
+	}
+
+	surnameA, initialA := playerName(a)
+	surnameB, initialB := playerName(b)
+
+	if surnameA == "" || surnameA != surnameB {
+		return false
+	}
+
+	return initialA == "" || initialB == "" || initialA == initialB
+}
@@ -0,0 +1,167 @@
+package pgn
+
+import "testing"
+
+const duplicateDatabase = `[Event "TWIC 1500"]
+[Date "2023.05.10"]
+[White "Carlsen, Magnus"]
+[Black "Nakamura, Hikaru"]
+[Result "1-0"]
+
+1. e4 e5 2. Nf3 Nc6 3. Bb5 1-0
+
+[Event "Norway Chess"]
+[Site "Stavanger"]
+[Date "2023.05.11"]
+[White "Magnus Carlsen"]
+[Black "Nakamura,H"]
+[WhiteElo "2853"]
+[BlackElo "2775"]
+[Result "1-0"]
+
+1. e4 e5 2. Nf3 Nc6 3. Bb5 {Ruy Lopez} 1-0
+
+[Event "Other game, same moves"]
+[Date "2023.05.10"]
+[White "Caruana, Fabiano"]
+[Black "Nakamura, Hikaru"]
+[Result "1-0"]
+
+1. e4 e5 2. Nf3 Nc6 3. Bb5 1-0
+
+[Event "Too late"]
+[Date "2023.06.10"]
+[White "Carlsen, M"]
+[Black "Nakamura, Hikaru"]
+[Result "1-0"]
+
+1. e4 e5 2. Nf3 Nc6 3. Bb5 1-0
+
+[Event "Different moves"]
+[Date "2023.05.10"]
+[White "Carlsen, Magnus"]
+[Black "Nakamura, Hikaru"]
+[Result "1-0"]
+
+1. e4 e5 2. Nf3 Nc6 3. Bc4 1-0
+
+[Event "Partial date"]
+[Date "2023.??.??"]
+[White "Carlsen"]
+[Black "Nakamura, Hikaru"]
+[Result "*"]
+
+1. e4 e5 2. Nf3+ Nc6 3. Bb5 *
+`
+
+func TestFindDuplicates(t *testing.T) {
+	games, err := NewGames(duplicateDatabase)
+	if err != nil {
+		t.Fatalf("NewGames() error: %v", err)
+	}
+
+	groups := FindDuplicates(games, DefaultDuplicateOptions)
+	if len(groups) != 1 {
+		t.Fatalf("FindDuplicates() = %+v, want one group", groups)
+	}
+
+	group := groups[0]
+	if len(group.Games) != 3 || group.Games[0] != 0 || group.Games[1] != 1 || group.Games[2] != 5 {
+		t.Errorf("group games = %v, want [0 1 5]", group.Games)
+	}
+	if group.Keep != 1 {
+		t.Errorf("group keeps game %d, want the most complete copy 1", group.Keep)
+	}
+
+	// With a month of tolerance the later copy joins the group.
+	groups = FindDuplicates(games, DuplicateOptions{DateTolerance: 31})
+	if len(groups) != 1 || len(groups[0].Games) != 4 {
+		t.Errorf("FindDuplicates() with a month of tolerance = %+v", groups)
+	}
+
+	groups = FindDuplicates(games, DuplicateOptions{MovesOnly: true})
+	if len(groups) != 1 || len(groups[0].Games) != 5 {
+		t.Errorf("FindDuplicates() on moves only = %+v", groups)
+	}
+}
+
+func TestDedupe(t *testing.T) {
+	games, err := NewGames(duplicateDatabase)
+	if err != nil {
+		t.Fatalf("NewGames() error: %v", err)
+	}
+
+	kept, groups := Dedupe(games, DefaultDuplicateOptions)
+	if len(groups) != 1 {
+		t.Fatalf("Dedupe() found %d groups, want 1", len(groups))
+	}
+
+	events := []string{}
+	for _, g := range kept {
+		events = append(events, g.Event())
+	}
+
+	expected := []string{"Norway Chess", "Other game, same moves", "Too late", "Different moves"}
+	if len(events) != len(expected) {
+		t.Fatalf("Dedupe() kept %v, want %v", events, expected)
+	}
+	for i := range expected {
+		if events[i] != expected[i] {
+			t.Errorf("Dedupe() kept %v, want %v", events, expected)
+			break
+		}
+	}
+}
+
+func TestSamePlayer(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected bool
+	}{
+		{"Carlsen, Magnus", "Magnus Carlsen", true},
+		{"Carlsen, Magnus", "Carlsen,M.", true},
+		{"Carlsen, Magnus", "carlsen", true},
+		{"Ding, Liren", "Ding Liren", false},
+		{"Ding, Liren", "Ding, L", true},
+		{"Hübner, Robert", "Hubner, R", true},
+		{"Carlsen, Magnus", "Carlsen, Henrik", false},
+		{"Nakamura, Hikaru", "Caruana, Fabiano", false},
+		{"?", "?", true},
+		{"", "Carlsen", false},
+	}
+
+	for _, tt := range tests {
+		if got := SamePlayer(tt.a, tt.b); got != tt.expected {
+			t.Errorf("SamePlayer(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.expected)
+		}
+	}
+}
+
+func TestMoveHash(t *testing.T) {
+	hash := func(pgn string) uint64 {
+		t.Helper()
+
+		g, err := New(pgn)
+		if err != nil {
+			t.Fatalf("New(%q) error: %v", pgn, err)
+		}
+		return g.MoveHash()
+	}
+
+	canonical := hash("[Result \"*\"]\n\n1. e4 Nf6 2. e5 d5 3. exd6 Nc6 4. Nf3 Bg4 5. Be2 Qd7 6. O-O *")
+
+	spellings := []string{
+		"[Result \"*\"]\n\n1. e2e4 Nf6 2. e5 d5 3. exd6e.p. Nc6 4. Ngf3 Bg4 5. Bfe2 Qdd7 6. 0-0 *",
+		"[FEN \"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR  w  KQkq -  0 1\"]\n[Result \"*\"]\n\n" +
+			"1. e4 Nf6 2. e5 d5 3. exd6 Nc6 4. Nf3+ Bg4 5. Be2! Qd7 6. O-O *",
+	}
+	for _, pgn := range spellings {
+		if got := hash(pgn); got != canonical {
+			t.Errorf("MoveHash(%q) differs from the canonical spelling", pgn)
+		}
+	}
+
+	if hash("[Result \"*\"]\n\n1. e4 Nf6 2. e5 d5 3. exd6 Nc6 4. Nf3 Bg4 5. Be2 Qd7 *") == canonical {
+		t.Errorf("MoveHash() of a shorter game equals the full game's")
+	}
+}
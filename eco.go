@@ -0,0 +1,121 @@
+package pgn
+
+import (
+	_ "embed"
+	"strings"
+	"sync"
+)
+
+// eco.tsv is derived from the lichess chess-openings database
+// (https://github.com/lichess-org/chess-openings), released under CC0.
+//
+//go:embed data/eco.tsv
+var ecoTable string
+
+// Opening is an entry of the ECO classification.
+type Opening struct {
+	ECO       string
+	Name      string
+	Variation string
+	// Ply is the ply of the game at which the opening was recognised.
+	Ply int
+}
+
+func (o Opening) FullName() string {
+	if o.Variation == "" {
+		return o.Name
+	}
+
+	return o.Name + ": " + o.Variation
+}
+
+var (
+	ecoOnce      sync.Once
+	ecoPositions map[uint64]Opening
+)
+
+func loadECO() {
+	ecoPositions = map[uint64]Opening{}
+
+	for i, line := range strings.Split(strings.TrimSpace(ecoTable), "\n") {
+		if i == 0 {
+			continue
+		}
+
+		fields := strings.Split(line, "\t")
+		if len(fields) != 3 {
+			continue
+		}
+
+		h, err := HashFEN(fields[2])
+		if err != nil {
+			continue
+		}
+
+		name, variation, _ := strings.Cut(fields[1], ": ")
+		ecoPositions[h] = Opening{ECO: fields[0], Name: name, Variation: variation}
+	}
+}
+
+// LookupOpening returns the ECO entry whose position is pos, if any.
+func LookupOpening(pos *Position) (Opening, bool) {
+	ecoOnce.Do(loadECO)
+
+	o, ok := ecoPositions[pos.Hash()]
+	return o, ok
+}
+
+// DetectOpening walks the mainline of the game and returns the deepest
+// position found in the ECO table. Positions rather than move sequences are
+// compared, so transpositions are classified correctly. It returns nil when
+// no position of the game is in the table.
+func (g *Game) DetectOpening() (*Opening, error) {
+	positions, err := g.Positions()
+	if err != nil {
+		return nil, err
+	}
+
+	var found *Opening
+	for ply, pos := range positions {
+		if o, ok := LookupOpening(pos); ok {
+			o.Ply = ply
+			found = &o
+		}
+	}
+
+	return found, nil
+}
+
+// ClassifyOpening detects the opening of the game and fills its ECO, Opening
+// and Variation tags. Unless overwrite is true, tags that are already set are
+// kept and only missing ones are filled; the Opening and Variation are then
+// filled only when an existing ECO tag agrees with the detected opening, so
+// that the tags cannot contradict each other.
+func (g *Game) ClassifyOpening(overwrite bool) (*Opening, error) {
+	o, err := g.DetectOpening()
+	if err != nil || o == nil {
+		return o, err
+	}
+
+	if eco := g.GetTag("ECO"); !overwrite && eco != "" && eco != o.ECO {
+		return o, nil
+	}
+
+	setTag := func(name, value string) {
+		if g.GetTag(name) != "" && !overwrite {
+			return
+		}
+
+		if value == "" {
+			g.RemoveTag(name)
+			return
+		}
+		g.SetTag(name, value)
+	}
+
+	setTag("ECO", o.ECO)
+	setTag("Opening", o.Name)
+	setTag("Variation", o.Variation)
+
+	return o, nil
+}
@@ -0,0 +1,101 @@
+package pgn
+
+import "testing"
+
+func TestDetectOpening(t *testing.T) {
+	tests := []struct {
+		input     string
+		eco       string
+		name      string
+		variation string
+		ply       int
+	}{
+		{"1. e4 c5 2. Nf3 d6 3. d4 cxd4 4. Nxd4 Nf6 5. Nc3 a6 6. Be3 e5", "B90", "Sicilian Defense", "Najdorf Variation, English Attack", 11},
+		{"1. e4 e6", "C00", "French Defense", "", 2},
+		// The Queen's Gambit Declined reached by an English move order.
+		{"1. c4 e6 2. Nc3 d5 3. d4", "D31", "Queen's Gambit Declined", "Queen's Knight Variation", 5},
+	}
+
+	for _, tt := range tests {
+		game, err := New(tt.input)
+		if err != nil {
+			t.Fatalf("New(%q) error: %v", tt.input, err)
+		}
+
+		o, err := game.DetectOpening()
+		if err != nil {
+			t.Fatalf("DetectOpening() error: %v", err)
+		}
+		if o == nil {
+			t.Fatalf("DetectOpening(%q) found no opening", tt.input)
+		}
+
+		if o.ECO != tt.eco || o.Name != tt.name || o.Variation != tt.variation || o.Ply != tt.ply {
+			t.Errorf("DetectOpening(%q) = %+v, want %s %q %q at ply %d", tt.input, *o, tt.eco, tt.name, tt.variation, tt.ply)
+		}
+	}
+}
+
+func TestClassifyOpening(t *testing.T) {
+	input := `[Event "Test"]
+[ECO "A00"]
+[Result "*"]
+
+1. e4 c5 2. Nf3 d6 3. d4 cxd4 4. Nxd4 Nf6 5. Nc3 a6 *`
+
+	game, err := New(input)
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+
+	if _, err := game.ClassifyOpening(false); err != nil {
+		t.Fatalf("ClassifyOpening() error: %v", err)
+	}
+
+	if got := game.GetTag("ECO"); got != "A00" {
+		t.Errorf("ECO without overwrite = %q, want %q", got, "A00")
+	}
+	if got := game.GetTag("Opening"); got != "" {
+		t.Errorf("Opening without overwrite = %q, want it left unset next to the contradicting ECO", got)
+	}
+
+	if _, err := game.ClassifyOpening(true); err != nil {
+		t.Fatalf("ClassifyOpening() error: %v", err)
+	}
+
+	if got := game.GetTag("ECO"); got != "B90" {
+		t.Errorf("ECO with overwrite = %q, want %q", got, "B90")
+	}
+	if got := game.GetTag("Opening"); got != "Sicilian Defense" {
+		t.Errorf("Opening = %q, want %q", got, "Sicilian Defense")
+	}
+	if got := game.GetTag("Variation"); got != "Najdorf Variation" {
+		t.Errorf("Variation = %q, want %q", got, "Najdorf Variation")
+	}
+
+	game.RemoveTag("Opening")
+	game.RemoveTag("Variation")
+	if _, err := game.ClassifyOpening(false); err != nil {
+		t.Fatalf("ClassifyOpening() error: %v", err)
+	}
+
+	if got := game.GetTag("Opening"); got != "Sicilian Defense" {
+		t.Errorf("missing Opening next to ECO B90 = %q, want %q", got, "Sicilian Defense")
+	}
+	if got := game.GetTag("Variation"); got != "Najdorf Variation" {
+		t.Errorf("missing Variation next to ECO B90 = %q, want %q", got, "Najdorf Variation")
+	}
+
+	game.RemoveTag("ECO")
+	game.SetTag("Opening", "Open Sicilian")
+	if _, err := game.ClassifyOpening(false); err != nil {
+		t.Fatalf("ClassifyOpening() error: %v", err)
+	}
+
+	if got := game.GetTag("ECO"); got != "B90" {
+		t.Errorf("missing ECO = %q, want it filled with %q", got, "B90")
+	}
+	if got := game.GetTag("Opening"); got != "Open Sicilian" {
+		t.Errorf("Opening without overwrite = %q, want %q kept", got, "Open Sicilian")
+	}
+}
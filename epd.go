@@ -0,0 +1,296 @@
+package pgn
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// EPDOperation is an opcode of an EPD record with its operands, such as
+// bm Nf3 Nc3 or id "WAC.001".
+type EPDOperation struct {
+	Opcode   string
+	Operands []string
+}
+
+// EPD is an Extended Position Description record: a position without move
+// counters followed by operations.
+type EPD struct {
+	Position   *Position
+	Operations []EPDOperation
+}
+
+// ParseEPD parses a single EPD record. The hmvc and fmvn opcodes, when
+// present, set the position's halfmove clock and fullmove number.
+func ParseEPD(record string) (*EPD, error) {
+	// The four position fields may be separated by any whitespace; the rest
+	// of the record is operations.
+	fields := []string{}
+	rest := strings.TrimSpace(record)
+	for len(fields) < 4 && rest != "" {
+		end := strings.IndexFunc(rest, unicode.IsSpace)
+		if end < 0 {
+			end = len(rest)
+		}
+		fields = append(fields, rest[:end])
+		rest = strings.TrimLeftFunc(rest[end:], unicode.IsSpace)
+	}
+	if len(fields) < 4 {
+		return nil, fmt.Errorf("invalid EPD %q: expected at least 4 fields", record)
+	}
+
+	pos, err := ParseFEN(strings.Join(fields, " "))
+	if err != nil {
+		return nil, err
+	}
+
+	epd := &EPD{Position: pos}
+
+	if rest != "" {
+		if epd.Operations, err = parseEPDOperations(rest); err != nil {
+			return nil, fmt.Errorf("invalid EPD %q: %v", record, err)
+		}
+	}
+
+	if n, ok := epd.intOperand("hmvc"); ok {
+		pos.halfmove = n
+	}
+	if n, ok := epd.intOperand("fmvn"); ok && n > 0 {
+		pos.fullmove = n
+	}
+
+	return epd, nil
+}
+
+// parseEPDOperations splits the operations of a record. Each operation is
+// an opcode followed by operands and ends with a semicolon. Quoted operands
+// may hold spaces and semicolons.
+func parseEPDOperations(s string) ([]EPDOperation, error) {
+	operations := []EPDOperation{}
+	words := []string{}
+
+	var word strings.Builder
+	inWord, inQuote := false, false
+
+	endWord := func() {
+		if inWord {
+			words = append(words, word.String())
+			word.Reset()
+			inWord = false
+		}
+	}
+
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+
+		switch {
+		case inQuote && ch == '"':
+			inQuote = false
+			endWord()
+		case inQuote:
+			word.WriteByte(ch)
+		case ch == '"':
+			endWord()
+			inQuote, inWord = true, true
+		case ch == ';':
+			endWord()
+			if len(words) == 0 {
+				return nil, fmt.Errorf("empty operation")
+			}
+			operations = append(operations, EPDOperation{Opcode: words[0], Operands: words[1:]})
+			words = []string{}
+		case ch == ' ' || ch == '\t':
+			endWord()
+		default:
+			word.WriteByte(ch)
+			inWord = true
+		}
+	}
+
+	if inQuote {
+		return nil, fmt.Errorf("unterminated string operand")
+	}
+
+	endWord()
+	if len(words) > 0 {
+		return nil, fmt.Errorf("operation %q is missing its semicolon", words[0])
+	}
+
+	return operations, nil
+}
+
+// Get returns the operands of the first operation with the given opcode.
+func (e *EPD) Get(opcode string) ([]string, bool) {
+	for _, op := range e.Operations {
+		if op.Opcode == opcode {
+			return op.Operands, true
+		}
+	}
+
+	return nil, false
+}
+
+// Set replaces the operands of the operation with the given opcode, adding
+// the operation at the end when the record does not have it.
+func (e *EPD) Set(opcode string, operands ...string) {
+	for i, op := range e.Operations {
+		if op.Opcode == opcode {
+			e.Operations[i].Operands = operands
+			return
+		}
+	}
+
+	e.Operations = append(e.Operations, EPDOperation{Opcode: opcode, Operands: operands})
+}
+
+// Remove removes the operation with the given opcode.
+func (e *EPD) Remove(opcode string) {
+	kept := e.Operations[:0]
+	for _, op := range e.Operations {
+		if op.Opcode != opcode {
+			kept = append(kept, op)
+		}
+	}
+
+	e.Operations = kept
+}
+
+func (e *EPD) intOperand(opcode string) (int, bool) {
+	operands, ok := e.Get(opcode)
+	if !ok || len(operands) != 1 {
+		return 0, false
+	}
+
+	n, err := strconv.Atoi(operands[0])
+	if err != nil {
+		return 0, false
+	}
+
+	return n, true
+}
+
+// String writes the record as a single EPD line.
+func (e *EPD) String() string {
+	fields := strings.Fields(e.Position.FEN())
+
+	var sb strings.Builder
+	sb.WriteString(strings.Join(fields[:4], " "))
+
+	for _, op := range e.Operations {
+		sb.WriteString(" ")
+		sb.WriteString(op.Opcode)
+
+		for _, operand := range op.Operands {
+			sb.WriteString(" ")
+			if needsQuotes(op.Opcode, operand) {
+				sb.WriteString(`"` + strings.ReplaceAll(operand, `"`, "") + `"`)
+			} else {
+				sb.WriteString(operand)
+			}
+		}
+
+		sb.WriteString(";")
+	}
+
+	return sb.String()
+}
+
+// needsQuotes reports whether an operand is written as a string: always for
+// the id and comment opcodes, and otherwise when it would not survive as a
+// bare word.
+func needsQuotes(opcode, operand string) bool {
+	if opcode == "id" || (len(opcode) == 2 && opcode[0] == 'c' && opcode[1] >= '0' && opcode[1] <= '9') {
+		return true
+	}
+
+	return operand == "" || strings.ContainsAny(operand, " \t;\"")
+}
+
+// ReadEPD reads EPD records, one per line. Blank lines and lines starting
+// with '#' are skipped.
+func ReadEPD(r io.Reader) ([]*EPD, error) {
+	records := []*EPD{}
+
+	lines := bufio.NewScanner(r)
+	for n := 1; lines.Scan(); n++ {
+		line := strings.TrimSpace(lines.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		epd, err := ParseEPD(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", n, err)
+		}
+
+		records = append(records, epd)
+	}
+
+	if err := lines.Err(); err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
+
+// WriteEPD writes records one per line.
+func WriteEPD(w io.Writer, records []*EPD) error {
+	for _, epd := range records {
+		if _, err := io.WriteString(w, epd.String()+"\n"); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// EPDRecords returns an EPD record for every position of the game reached
+// after fromPly plies or more. Each record carries the hmvc and fmvn
+// counters, the move played from the position as pm, and an id naming the
+// game and ply.
+func (g *Game) EPDRecords(fromPly int) ([]*EPD, error) {
+	positions, err := g.Positions()
+	if err != nil {
+		return nil, err
+	}
+
+	plies := g.Plies()
+	records := []*EPD{}
+
+	for i := max(fromPly, 0); i < len(positions); i++ {
+		pos := positions[i]
+		epd := &EPD{Position: pos}
+
+		epd.Set("hmvc", strconv.Itoa(pos.halfmove))
+		epd.Set("fmvn", strconv.Itoa(pos.fullmove))
+		if i < len(plies) {
+			mv, err := pos.parseSAN(plies[i].SAN)
+			if err != nil {
+				return nil, fmt.Errorf("ply %d: %v", i+1, err)
+			}
+			epd.Set("pm", pos.san(mv))
+		}
+		epd.Set("id", fmt.Sprintf("%s ply %d", g.epdName(), i))
+
+		records = append(records, epd)
+	}
+
+	return records, nil
+}
+
+// epdName names the game in EPD ids from its players, or its event when
+// they are unknown.
+func (g *Game) epdName() string {
+	if g.White() != "" || g.Black() != "" {
+		return g.White() + " - " + g.Black()
+	}
+
+	if g.Event() != "" {
+		return g.Event()
+	}
+
+	return "game"
+}
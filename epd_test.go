@@ -0,0 +1,147 @@
+package pgn
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const wacRecords = `# Win at Chess
+2rr3k/pp3pp1/1nnqbN1p/3pN3/2pP4/2P3Q1/PPB4P/R4RK1 w - - bm Qg6; id "WAC.001";
+r1b1k2r/ppppnppp/2n2q2/2b5/3NP3/2P1B3/PP3PPP/RN1QKB1R w KQkq - bm Nxc6 Bg5; am Qd2; c0 "two moves; one quiet"; id "WAC.006";
+
+8/7p/5k2/5p2/p1p2P2/Pr1pPK2/1P1R3P/8 b - - hmvc 4; fmvn 39; bm Rxb2;
+`
+
+func TestReadEPD(t *testing.T) {
+	records, err := ReadEPD(strings.NewReader(wacRecords))
+	if err != nil {
+		t.Fatalf("ReadEPD() error: %v", err)
+	}
+
+	if len(records) != 3 {
+		t.Fatalf("len(records) = %d, want 3", len(records))
+	}
+
+	if id, _ := records[0].Get("id"); !reflect.DeepEqual(id, []string{"WAC.001"}) {
+		t.Errorf("id = %v, want [WAC.001]", id)
+	}
+
+	bm, _ := records[1].Get("bm")
+	if !reflect.DeepEqual(bm, []string{"Nxc6", "Bg5"}) {
+		t.Errorf("bm = %v, want [Nxc6 Bg5]", bm)
+	}
+	if c0, _ := records[1].Get("c0"); !reflect.DeepEqual(c0, []string{"two moves; one quiet"}) {
+		t.Errorf("c0 = %v, want [two moves; one quiet]", c0)
+	}
+	if _, ok := records[1].Get("pm"); ok {
+		t.Errorf("Get(pm) found an operation the record does not have")
+	}
+
+	pos := records[2].Position
+	if pos.HalfmoveClock() != 4 || pos.FullmoveNumber() != 39 || pos.Turn() != Black {
+		t.Errorf("clocks = %d, %d, want 4, 39", pos.HalfmoveClock(), pos.FullmoveNumber())
+	}
+
+	var buf bytes.Buffer
+	if err := WriteEPD(&buf, records); err != nil {
+		t.Fatalf("WriteEPD() error: %v", err)
+	}
+
+	expected := `2rr3k/pp3pp1/1nnqbN1p/3pN3/2pP4/2P3Q1/PPB4P/R4RK1 w - - bm Qg6; id "WAC.001";
+r1b1k2r/ppppnppp/2n2q2/2b5/3NP3/2P1B3/PP3PPP/RN1QKB1R w KQkq - bm Nxc6 Bg5; am Qd2; c0 "two moves; one quiet"; id "WAC.006";
+8/7p/5k2/5p2/p1p2P2/Pr1pPK2/1P1R3P/8 b - - hmvc 4; fmvn 39; bm Rxb2;
+`
+	if buf.String() != expected {
+		t.Errorf("WriteEPD() =\n%s\nwant\n%s", buf.String(), expected)
+	}
+}
+
+func TestParseEPDErrors(t *testing.T) {
+	for _, input := range []string{
+		"8/8/8/8/8/8/8/8 w",
+		"4k3/8/8/8/8/8/8/4K3 w - - bm Qg6",
+		`4k3/8/8/8/8/8/8/4K3 w - - id "open;`,
+		"4k3/8/8/8/8/8/8/4K3 w - - ;",
+	} {
+		if _, err := ParseEPD(input); err == nil {
+			t.Errorf("ParseEPD(%q) expected error", input)
+		}
+	}
+}
+
+func TestParseEPDWhitespace(t *testing.T) {
+	epd, err := ParseEPD("4k3/8/8/8/8/8/8/4K3\tw  -\t\t-   bm Kd2;\tid \"x  y\";")
+	if err != nil {
+		t.Fatalf("ParseEPD() error: %v", err)
+	}
+
+	if got := epd.Position.FEN(); got != "4k3/8/8/8/8/8/8/4K3 w - - 0 1" {
+		t.Errorf("FEN() = %q", got)
+	}
+	if bm, _ := epd.Get("bm"); !reflect.DeepEqual(bm, []string{"Kd2"}) {
+		t.Errorf("bm = %v, want [Kd2]", bm)
+	}
+	if id, _ := epd.Get("id"); !reflect.DeepEqual(id, []string{"x  y"}) {
+		t.Errorf("id = %v, want [x  y]", id)
+	}
+
+	if epd, err := ParseEPD("4k3/8/8/8/8/8/8/4K3 w - -"); err != nil || len(epd.Operations) != 0 {
+		t.Errorf("ParseEPD() without operations = %v, %v", epd, err)
+	}
+}
+
+func TestEPDSet(t *testing.T) {
+	epd, err := ParseEPD(`4k3/8/8/8/8/8/8/4K3 w - - bm Kd2; id "x";`)
+	if err != nil {
+		t.Fatalf("ParseEPD() error: %v", err)
+	}
+
+	epd.Set("bm", "Ke2", "Kf2")
+	epd.Set("ce", "0")
+	epd.Remove("id")
+
+	if got, want := epd.String(), "4k3/8/8/8/8/8/8/4K3 w - - bm Ke2 Kf2; ce 0;"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestGameEPDRecords(t *testing.T) {
+	game, err := New(`[White "Anderssen"]
+[Black "Kieseritzky"]
+[Result "*"]
+
+1. e4 e5 2. f4 *`)
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+
+	records, err := game.EPDRecords(2)
+	if err != nil {
+		t.Fatalf("EPDRecords() error: %v", err)
+	}
+
+	expected := []string{
+		`rnbqkbnr/pppp1ppp/8/4p3/4P3/8/PPPP1PPP/RNBQKBNR w KQkq e6 hmvc 0; fmvn 2; pm f4; id "Anderssen - Kieseritzky ply 2";`,
+		`rnbqkbnr/pppp1ppp/8/4p3/4PP2/8/PPPP2PP/RNBQKBNR b KQkq f3 hmvc 0; fmvn 2; id "Anderssen - Kieseritzky ply 3";`,
+	}
+
+	if len(records) != len(expected) {
+		t.Fatalf("len(EPDRecords()) = %d, want %d", len(records), len(expected))
+	}
+
+	for i, want := range expected {
+		if got := records[i].String(); got != want {
+			t.Errorf("record %d = %q, want %q", i, got, want)
+		}
+
+		again, err := ParseEPD(records[i].String())
+		if err != nil {
+			t.Fatalf("ParseEPD() error: %v", err)
+		}
+		if again.Position.FEN() != records[i].Position.FEN() {
+			t.Errorf("record %d FEN after round trip = %q, want %q", i, again.Position.FEN(), records[i].Position.FEN())
+		}
+	}
+}
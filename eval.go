@@ -0,0 +1,118 @@
+package pgn
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Evaluation is an engine evaluation from white's point of view, as written
+// in a [%eval ...] command annotation.
+type Evaluation struct {
+	// Centipawns is the score in hundredths of a pawn when Mate is zero.
+	Centipawns int
+	// Mate is the number of moves to mate, negative when black mates.
+	Mate int
+	// Depth is the search depth, or zero when unknown.
+	Depth int
+}
+
+// IsMate reports whether the evaluation is a forced mate.
+func (e Evaluation) IsMate() bool {
+	return e.Mate != 0
+}
+
+// MateScore is the score Score gives to a position where mate has been
+// delivered.
+const MateScore = 100000
+
+// Score returns the evaluation as a single number of centipawns from white's
+// point of view, so that evaluations can be compared. A mate in n scores
+// MateScore-n for white and -(MateScore-n) for black.
+func (e Evaluation) Score() int {
+	switch {
+	case e.Mate > 0:
+		return MateScore - e.Mate
+	case e.Mate < 0:
+		return -MateScore - e.Mate
+	}
+
+	return e.Centipawns
+}
+
+// String writes the evaluation in %eval form: "0.35", "#-3" or "0.35,20"
+// when the depth is known.
+func (e Evaluation) String() string {
+	var s string
+	if e.IsMate() {
+		s = fmt.Sprintf("#%d", e.Mate)
+	} else {
+		s = strconv.FormatFloat(float64(e.Centipawns)/100, 'f', -1, 64)
+	}
+
+	if e.Depth > 0 {
+		s += "," + strconv.Itoa(e.Depth)
+	}
+
+	return s
+}
+
+// ParseEvaluation parses the value of an %eval command: a score in pawns
+// such as "0.35" or "-1.2", or a mate such as "#-3", optionally followed by
+// ",depth".
+func ParseEvaluation(s string) (Evaluation, error) {
+	var e Evaluation
+
+	value, depth, hasDepth := strings.Cut(strings.TrimSpace(s), ",")
+	if hasDepth {
+		d, err := strconv.Atoi(strings.TrimSpace(depth))
+		if err != nil || d < 0 {
+			return e, fmt.Errorf("invalid evaluation depth %q", s)
+		}
+		e.Depth = d
+	}
+
+	if strings.HasPrefix(value, "#") {
+		mate, err := strconv.Atoi(strings.TrimPrefix(value[1:], "+"))
+		if err != nil || mate == 0 {
+			return e, fmt.Errorf("invalid mate evaluation %q", s)
+		}
+		e.Mate = mate
+		return e, nil
+	}
+
+	pawns, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return e, fmt.Errorf("invalid evaluation %q", s)
+	}
+
+	if pawns < 0 {
+		e.Centipawns = int(pawns*100 - 0.5)
+	} else {
+		e.Centipawns = int(pawns*100 + 0.5)
+	}
+
+	return e, nil
+}
+
+// Evaluation returns the engine evaluation after the ply, from its %eval
+// annotation.
+func (p Ply) Evaluation() (Evaluation, bool) {
+	value, ok := p.Command("eval")
+	if !ok {
+		return Evaluation{}, false
+	}
+
+	e, err := ParseEvaluation(value)
+	if err != nil {
+		return Evaluation{}, false
+	}
+
+	return e, true
+}
+
+// SetEvaluation writes e into the %eval annotation of the given ply,
+// counted from 1.
+func (g *Game) SetEvaluation(ply int, e Evaluation) error {
+	return g.SetCommand(ply, "eval", e.String())
+}
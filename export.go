@@ -0,0 +1,178 @@
+package pgn
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// exportLineLength is the width movetext is wrapped at, as recommended for
+// PGN export format.
+const exportLineLength = 80
+
+// sevenTagRoster lists the tags that come first, in this order, in exported
+// games.
+var sevenTagRoster = []string{"Event", "Site", "Date", "Round", "White", "Black", "Result"}
+
+// PGN returns the game in PGN export format: the seven tag roster first,
+// then the other tags, then the movetext with its NAGs and comments wrapped
+// at 80 columns.
+func (g *Game) PGN() string {
+	var sb strings.Builder
+
+	for _, name := range g.exportTagOrder() {
+		fmt.Fprintf(&sb, "[%s \"%s\"]\n", name, escapeTagValue(g.tags[name]))
+	}
+
+	if len(g.tags) > 0 {
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString(wrapTokens(g.movetextTokens(), exportLineLength))
+	sb.WriteString("\n")
+
+	return sb.String()
+}
+
+// WriteGames writes games in PGN export format, separated by blank lines.
+func WriteGames(w io.Writer, games []*Game) error {
+	for i, g := range games {
+		if i > 0 {
+			if _, err := io.WriteString(w, "\n"); err != nil {
+				return err
+			}
+		}
+
+		if _, err := io.WriteString(w, g.PGN()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (g *Game) exportTagOrder() []string {
+	names := []string{}
+	for _, name := range sevenTagRoster {
+		if _, ok := g.tags[name]; ok {
+			names = append(names, name)
+		}
+	}
+
+	others := []string{}
+	for name := range g.tags {
+		if !isSevenTagRoster(name) {
+			others = append(others, name)
+		}
+	}
+	sort.Strings(others)
+
+	return append(names, others...)
+}
+
+func isSevenTagRoster(name string) bool {
+	for _, n := range sevenTagRoster {
+		if n == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+func escapeTagValue(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value)
+}
+
+// exportComment writes a comment in braces. A closing brace cannot be
+// escaped in PGN, so it is dropped.
+func exportComment(comment string) string {
+	return "{" + strings.ReplaceAll(comment, "}", "") + "}"
+}
+
+// movetextTokens returns the movetext of the game as the units line wrapping
+// may not split: move numbers with their move, NAGs, comments, variations
+// and the result.
+func (g *Game) movetextTokens() []string {
+	return append(lineTokens(g.comments, g.Plies()), g.exportResult())
+}
+
+// lineTokens returns the tokens of a line of plies and the comments before
+// it. Black moves are numbered at the start of the line and after a comment
+// or variation.
+func lineTokens(comments []string, plies []Ply) []string {
+	tokens := []string{}
+	for _, c := range comments {
+		tokens = append(tokens, exportComment(c))
+	}
+
+	needNumber := true
+	for _, p := range plies {
+		switch {
+		case p.Color == White:
+			tokens = append(tokens, fmt.Sprintf("%d. %s", p.MoveNumber, p.SAN))
+		case needNumber:
+			tokens = append(tokens, fmt.Sprintf("%d... %s", p.MoveNumber, p.SAN))
+		default:
+			tokens = append(tokens, p.SAN)
+		}
+
+		for _, nag := range p.Annotations {
+			tokens = append(tokens, "$"+nag)
+		}
+
+		for _, c := range p.Comments {
+			tokens = append(tokens, exportComment(c))
+		}
+
+		for _, v := range p.Variations {
+			variation := lineTokens(v.Comments, v.Plies())
+			if len(variation) == 0 {
+				continue
+			}
+
+			variation[0] = "(" + variation[0]
+			variation[len(variation)-1] += ")"
+			tokens = append(tokens, variation...)
+		}
+
+		needNumber = len(p.Comments) > 0 || len(p.Variations) > 0
+	}
+
+	return tokens
+}
+
+func (g *Game) exportResult() string {
+	if g.result != "" {
+		return g.result
+	}
+
+	if result := g.tags["Result"]; isGameResult(result) {
+		return result
+	}
+
+	return "*"
+}
+
+// wrapTokens joins tokens with spaces, breaking lines before a token that
+// would take the line past width.
+func wrapTokens(tokens []string, width int) string {
+	var sb strings.Builder
+
+	lineLength := 0
+	for _, t := range tokens {
+		if lineLength > 0 && lineLength+1+len(t) > width {
+			sb.WriteString("\n")
+			lineLength = 0
+		} else if lineLength > 0 {
+			sb.WriteString(" ")
+			lineLength++
+		}
+
+		sb.WriteString(t)
+		lineLength += len(t)
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,131 @@
+package pgn
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExportPGN(t *testing.T) {
+	input := `[Annotator "me"]
+[White "Fischer, \"Bobby\""]
+[Result "1-0"]
+[Event "Casual"]
+
+{Start} 1. e4 e5!? 2. Nf3 {[%clk 0:02:55] develop} 2... Nc6 3. Bb5 $1 1-0`
+
+	game, err := New(input)
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+
+	expected := `[Event "Casual"]
+[White "Fischer, \"Bobby\""]
+[Result "1-0"]
+[Annotator "me"]
+
+{Start} 1. e4 e5 $5 2. Nf3 {[%clk 0:02:55] develop} 2... Nc6 3. Bb5 $1 1-0
+`
+
+	if got := game.PGN(); got != expected {
+		t.Errorf("PGN() =\n%s\nwant\n%s", got, expected)
+	}
+
+	if got := game.White(); got != `Fischer, "Bobby"` {
+		t.Errorf("White() = %q, want %q", got, `Fischer, "Bobby"`)
+	}
+
+	again, err := New(game.PGN())
+	if err != nil {
+		t.Fatalf("New() on exported PGN error: %v", err)
+	}
+	if again.PGN() != expected {
+		t.Errorf("exported PGN does not round trip:\n%s", again.PGN())
+	}
+}
+
+func TestExportLineWrapping(t *testing.T) {
+	game, err := New(`[Result "*"]
+
+1. e4 e5 2. Nf3 Nc6 3. Bb5 a6 4. Ba4 Nf6 5. O-O Be7 6. Re1 b5 7. Bb3 d6 8. c3 O-O 9. h3 Nb8 10. d4 Nbd7 *`)
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+
+	for _, line := range strings.Split(strings.TrimSpace(game.PGN()), "\n") {
+		if len(line) > exportLineLength {
+			t.Errorf("line longer than %d columns: %q", exportLineLength, line)
+		}
+		if strings.HasSuffix(line, ".") {
+			t.Errorf("line ends with a move number: %q", line)
+		}
+	}
+}
+
+func TestExportVariations(t *testing.T) {
+	input := `[Result "*"]
+
+1. e4 (1. d4 d5 (1... Nf6 2. c4) 2. c4 {Queen's Gambit}) ({Or} 1. c4) 1... e5 2. Nf3 (2. f4 exf4) Nc6 *`
+
+	game, err := New(input)
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+
+	plies := game.Plies()
+	if len(plies) != 4 {
+		t.Fatalf("len(Plies()) = %d, want 4", len(plies))
+	}
+
+	variations := plies[0].Variations
+	if len(variations) != 2 {
+		t.Fatalf("len(Variations) of 1. e4 = %d, want 2", len(variations))
+	}
+
+	nested := variations[0].Plies()[1].Variations
+	if len(nested) != 1 || nested[0].Plies()[0].SAN != "Nf6" {
+		t.Errorf("nested variation = %v, want 1... Nf6 2. c4", nested)
+	}
+
+	if got := variations[1].Comments; len(got) != 1 || got[0] != "Or" {
+		t.Errorf("variation comments = %v, want [Or]", got)
+	}
+
+	expected := `[Result "*"]
+
+1. e4 (1. d4 d5 (1... Nf6 2. c4) 2. c4 {Queen's Gambit}) ({Or} 1. c4) 1... e5
+2. Nf3 (2. f4 exf4) 2... Nc6 *
+`
+	if got := game.PGN(); got != expected {
+		t.Errorf("PGN() =\n%s\nwant\n%s", got, expected)
+	}
+
+	again, err := New(game.PGN())
+	if err != nil {
+		t.Fatalf("New() on exported PGN error: %v", err)
+	}
+	if again.PGN() != expected {
+		t.Errorf("exported PGN does not round trip:\n%s", again.PGN())
+	}
+}
+
+func TestAddVariation(t *testing.T) {
+	game, err := New("[Result \"*\"]\n\n1. e4 e5 2. Nf3 *")
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+
+	if _, err := game.AddVariation(2, []string{"c5", "Nf3", "d6"}); err != nil {
+		t.Fatalf("AddVariation() error: %v", err)
+	}
+	if _, err := game.AddVariation(3, []string{"Qh5", "Ke6"}); err == nil {
+		t.Errorf("AddVariation() with an illegal move expected error")
+	}
+	if err := game.AddAnnotation(3, "2"); err != nil {
+		t.Fatalf("AddAnnotation() error: %v", err)
+	}
+
+	expected := "[Result \"*\"]\n\n1. e4 e5 (1... c5 2. Nf3 d6) 2. Nf3 $2 *\n"
+	if got := game.PGN(); got != expected {
+		t.Errorf("PGN() = %q, want %q", got, expected)
+	}
+}
@@ -0,0 +1,406 @@
+package pgn
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const StartingFEN = "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1"
+
+func StartingPosition() *Position {
+	pos, _ := ParseFEN(StartingFEN)
+	return pos
+}
+
+// ParseFEN parses a position in Forsyth-Edwards Notation. The halfmove clock
+// and fullmove number may be omitted, as they are in EPD.
+func ParseFEN(fen string) (*Position, error) {
+	return ParseVariantFEN(fen, Standard)
+}
+
+// ParseVariantFEN parses a FEN for a position of the given variant. It
+// accepts the lichess extensions: crazyhouse pockets in brackets after the
+// piece placement, promoted pieces marked with a tilde, and the three-check
+// counters, either as remaining checks ("3+3") after the en passant square
+// or as checks given ("+0+0") at the end. Check counters are rejected in
+// other variants.
+func ParseVariantFEN(fen string, v Variant) (*Position, error) {
+	pos := newEmptyPosition()
+	if v != Standard {
+		pos.variant = v
+	}
+	pos.chess960 = v == Chess960
+
+	fields := []string{}
+	for _, field := range strings.Fields(fen) {
+		if strings.Contains(field, "+") {
+			if v != ThreeCheck {
+				return nil, fmt.Errorf("invalid FEN %q: check counter %q outside three-check", fen, field)
+			}
+			if err := pos.parseChecks(field); err != nil {
+				return nil, fmt.Errorf("invalid FEN %q: %v", fen, err)
+			}
+			continue
+		}
+		fields = append(fields, field)
+	}
+
+	if len(fields) < 4 || len(fields) > 6 {
+		return nil, fmt.Errorf("invalid FEN %q: expected 4 to 6 fields, got %d", fen, len(fields))
+	}
+
+	if err := pos.parsePlacement(fields[0]); err != nil {
+		return nil, fmt.Errorf("invalid FEN %q: %v", fen, err)
+	}
+
+	switch fields[1] {
+	case "w":
+		pos.turn = White
+	case "b":
+		pos.turn = Black
+	default:
+		return nil, fmt.Errorf("invalid FEN %q: bad side to move %q", fen, fields[1])
+	}
+
+	if err := pos.parseCastling(fields[2]); err != nil {
+		return nil, fmt.Errorf("invalid FEN %q: %v", fen, err)
+	}
+
+	if fields[3] != "-" {
+		sq, err := ParseSquare(fields[3])
+		if err != nil {
+			return nil, fmt.Errorf("invalid FEN %q: bad en passant square: %v", fen, err)
+		}
+		pos.ep = sq
+	}
+
+	if len(fields) > 4 {
+		n, err := strconv.Atoi(fields[4])
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid FEN %q: bad halfmove clock %q", fen, fields[4])
+		}
+		pos.halfmove = n
+	}
+
+	if len(fields) > 5 {
+		n, err := strconv.Atoi(fields[5])
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("invalid FEN %q: bad fullmove number %q", fen, fields[5])
+		}
+		pos.fullmove = n
+	}
+
+	if err := v.validate(pos); err != nil {
+		return nil, fmt.Errorf("invalid FEN %q: %v", fen, err)
+	}
+
+	return pos, nil
+}
+
+func (pos *Position) parseChecks(field string) error {
+	var a, b int
+
+	if strings.HasPrefix(field, "+") {
+		if _, err := fmt.Sscanf(field, "+%d+%d", &a, &b); err != nil {
+			return fmt.Errorf("bad check counter %q", field)
+		}
+		pos.checks = [2]int{a, b}
+		return nil
+	}
+
+	if _, err := fmt.Sscanf(field, "%d+%d", &a, &b); err != nil {
+		return fmt.Errorf("bad check counter %q", field)
+	}
+	pos.checks = [2]int{3 - a, 3 - b}
+
+	return nil
+}
+
+func (pos *Position) parsePlacement(placement string) error {
+	if i := strings.IndexByte(placement, '['); i >= 0 {
+		if !strings.HasSuffix(placement, "]") {
+			return fmt.Errorf("unterminated pocket in %q", placement)
+		}
+		if err := pos.parsePockets(placement[i+1 : len(placement)-1]); err != nil {
+			return err
+		}
+		placement = placement[:i]
+	}
+
+	ranks := strings.Split(placement, "/")
+	if len(ranks) == 9 {
+		if err := pos.parsePockets(ranks[8]); err != nil {
+			return err
+		}
+		ranks = ranks[:8]
+	}
+
+	if len(ranks) != 8 {
+		return fmt.Errorf("expected 8 ranks, got %d", len(ranks))
+	}
+
+	for i, row := range ranks {
+		rank := 7 - i
+		file := 0
+
+		for j := 0; j < len(row); j++ {
+			ch := row[j]
+
+			if isDigit(ch) {
+				file += int(ch - '0')
+				continue
+			}
+
+			if ch == '~' && file > 0 {
+				pos.promoted[newSquare(file-1, rank)] = true
+				continue
+			}
+
+			piece, ok := pieceFromFEN(ch)
+			if !ok {
+				return fmt.Errorf("unexpected character %q in rank %d", ch, rank+1)
+			}
+
+			if file > 7 {
+				return fmt.Errorf("too many squares in rank %d", rank+1)
+			}
+
+			pos.board[newSquare(file, rank)] = piece
+			file++
+		}
+
+		if file != 8 {
+			return fmt.Errorf("rank %d has %d squares", rank+1, file)
+		}
+	}
+
+	return nil
+}
+
+func (pos *Position) parsePockets(pockets string) error {
+	for i := 0; i < len(pockets); i++ {
+		piece, ok := pieceFromFEN(pockets[i])
+		if !ok || piece.Type == King {
+			return fmt.Errorf("bad piece %q in pocket", pockets[i])
+		}
+		pos.pockets[piece.Color][piece.Type]++
+	}
+
+	return nil
+}
+
+// parseCastling accepts standard KQkq, X-FEN, where K and Q name the
+// outermost rook on each side, and Shredder-FEN, where rooks are named by
+// their file.
+func (pos *Position) parseCastling(field string) error {
+	if field == "-" {
+		return nil
+	}
+
+	for i := 0; i < len(field); i++ {
+		ch := field[i]
+
+		color := White
+		if 'a' <= ch && ch <= 'z' {
+			color = Black
+		}
+
+		king := pos.kingSquare(color)
+		if king == NoSquare || king.Rank() != backRank(color) {
+			return fmt.Errorf("castling field %q: no king on the back rank", field)
+		}
+
+		rook := NoSquare
+		switch ch {
+		case 'K', 'k':
+			rook = pos.outermostRook(color, king, kingSide)
+		case 'Q', 'q':
+			rook = pos.outermostRook(color, king, queenSide)
+		default:
+			lower := ch | 0x20
+			if lower < 'a' || lower > 'h' {
+				return fmt.Errorf("bad castling field %q", field)
+			}
+
+			sq := newSquare(int(lower-'a'), backRank(color))
+			if pos.board[sq] == (Piece{Type: Rook, Color: color}) && sq != king {
+				rook = sq
+			}
+			pos.chess960 = true
+		}
+
+		if rook == NoSquare {
+			return fmt.Errorf("castling field %q: no rook for %q", field, ch)
+		}
+
+		side := kingSide
+		if rook.File() < king.File() {
+			side = queenSide
+		}
+		pos.castling[color][side] = rook
+
+		if king.File() != 4 || (rook.File() != 0 && rook.File() != 7) {
+			pos.chess960 = true
+		}
+	}
+
+	return nil
+}
+
+func (pos *Position) outermostRook(c Color, king Square, side int) Square {
+	rank := backRank(c)
+	rook := Piece{Type: Rook, Color: c}
+
+	if side == kingSide {
+		for file := 7; file > king.File(); file-- {
+			if pos.board[newSquare(file, rank)] == rook {
+				return newSquare(file, rank)
+			}
+		}
+	} else {
+		for file := 0; file < king.File(); file++ {
+			if pos.board[newSquare(file, rank)] == rook {
+				return newSquare(file, rank)
+			}
+		}
+	}
+
+	return NoSquare
+}
+
+func (pos *Position) FEN() string {
+	var sb strings.Builder
+
+	sb.WriteString(pos.placement())
+	sb.WriteByte(' ')
+
+	if pos.turn == White {
+		sb.WriteByte('w')
+	} else {
+		sb.WriteByte('b')
+	}
+
+	sb.WriteByte(' ')
+	sb.WriteString(pos.castlingField())
+	sb.WriteByte(' ')
+	sb.WriteString(pos.ep.String())
+
+	if pos.variant == ThreeCheck {
+		fmt.Fprintf(&sb, " %d+%d", 3-pos.checks[White], 3-pos.checks[Black])
+	}
+
+	fmt.Fprintf(&sb, " %d %d", pos.halfmove, pos.fullmove)
+
+	return sb.String()
+}
+
+func (pos *Position) placement() string {
+	var sb strings.Builder
+
+	sb.WriteString(pos.ranks(true))
+
+	if pos.variant == Crazyhouse {
+		sb.WriteByte('[')
+		for c := White; c <= Black; c++ {
+			for pt := Queen; pt >= Pawn; pt-- {
+				for i := 0; i < pos.pockets[c][pt]; i++ {
+					sb.WriteString(Piece{Type: pt, Color: c}.String())
+				}
+			}
+		}
+		sb.WriteByte(']')
+	}
+
+	return sb.String()
+}
+
+// ranks writes the eight ranks of the piece placement, with the crazyhouse
+// markers of promoted pieces when promoted is true.
+func (pos *Position) ranks(promoted bool) string {
+	var sb strings.Builder
+
+	for rank := 7; rank >= 0; rank-- {
+		empty := 0
+
+		for file := 0; file < 8; file++ {
+			piece := pos.board[newSquare(file, rank)]
+			if piece.IsEmpty() {
+				empty++
+				continue
+			}
+
+			if empty > 0 {
+				sb.WriteByte(byte('0' + empty))
+				empty = 0
+			}
+			sb.WriteString(piece.String())
+
+			if promoted && pos.promoted[newSquare(file, rank)] {
+				sb.WriteByte('~')
+			}
+		}
+
+		if empty > 0 {
+			sb.WriteByte(byte('0' + empty))
+		}
+
+		if rank > 0 {
+			sb.WriteByte('/')
+		}
+	}
+
+	return sb.String()
+}
+
+// castlingField writes the castling rights in X-FEN, which is identical to
+// standard FEN for standard chess: a right is written as K or Q when its rook
+// is the outermost one on that side, and as the rook's file otherwise.
+func (pos *Position) castlingField() string {
+	return pos.formatCastling(false)
+}
+
+func (pos *Position) formatCastling(shredder bool) string {
+	field := ""
+
+	for c := White; c <= Black; c++ {
+		for side := kingSide; side <= queenSide; side++ {
+			rook := pos.castling[c][side]
+			if rook == NoSquare {
+				continue
+			}
+
+			var ch byte
+			switch {
+			case shredder:
+				ch = byte('A' + rook.File())
+			case pos.outermostRook(c, pos.kingSquare(c), side) != rook:
+				ch = byte('A' + rook.File())
+			case side == kingSide:
+				ch = 'K'
+			default:
+				ch = 'Q'
+			}
+
+			if c == Black {
+				ch |= 0x20
+			}
+			field += string(ch)
+		}
+	}
+
+	if field == "" {
+		return "-"
+	}
+
+	return field
+}
+
+// ShredderFEN returns the FEN of the position with the castling rights
+// written as rook files, as used by Chess960 software.
+func (pos *Position) ShredderFEN() string {
+	fields := strings.Fields(pos.FEN())
+	fields[2] = pos.formatCastling(true)
+
+	return strings.Join(fields, " ")
+}
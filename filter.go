@@ -0,0 +1,210 @@
+package pgn
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Filter selects games. Filters are composed with And, Or and Not.
+type Filter func(g *Game) bool
+
+// And matches games that every filter matches. With no filters it matches
+// every game.
+func And(filters ...Filter) Filter {
+	return func(g *Game) bool {
+		for _, f := range filters {
+			if !f(g) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Or matches games that any of the filters matches.
+func Or(filters ...Filter) Filter {
+	return func(g *Game) bool {
+		for _, f := range filters {
+			if f(g) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Not matches the games that f does not.
+func Not(f Filter) Filter {
+	return func(g *Game) bool {
+		return !f(g)
+	}
+}
+
+// FilterGames returns the games that f matches, in order.
+func FilterGames(games []*Game, f Filter) []*Game {
+	matched := []*Game{}
+	for _, g := range games {
+		if f(g) {
+			matched = append(matched, g)
+		}
+	}
+
+	return matched
+}
+
+// Player matches games in which a player whose name contains name, ignoring
+// case, had either color.
+func Player(name string) Filter {
+	name = strings.ToLower(name)
+	return func(g *Game) bool {
+		return strings.Contains(strings.ToLower(g.White()), name) || strings.Contains(strings.ToLower(g.Black()), name)
+	}
+}
+
+// EloRange matches games in which both players are rated from min to max.
+// A max of 0 leaves the range open. Games with a missing rating do not
+// match.
+func EloRange(min, max int) Filter {
+	inRange := func(elo int, ok bool) bool {
+		return ok && elo >= min && (max == 0 || elo <= max)
+	}
+
+	return func(g *Game) bool {
+		return inRange(g.WhiteElo()) && inRange(g.BlackElo())
+	}
+}
+
+// DateRange matches games played from one date to another, inclusive. A
+// zero date leaves that end open, and unknown parts of to, as in 2024.??.??,
+// extend it to the end of the year or month. Games without a known year do
+// not match.
+func DateRange(from, to Date) Filter {
+	if to.Month == 0 {
+		to.Month = 12
+	}
+	if to.Day == 0 {
+		to.Day = 31
+	}
+
+	return func(g *Game) bool {
+		d, err := g.ParsedDate()
+		if err != nil || d.IsZero() {
+			return false
+		}
+
+		return d.Compare(from) >= 0 && (to.IsZero() || d.Compare(to) <= 0)
+	}
+}
+
+// ECORange matches games whose ECO tag is from one code to another, such as
+// "B20" to "B99".
+func ECORange(from, to string) Filter {
+	return func(g *Game) bool {
+		eco := g.GetTag("ECO")
+		return len(eco) == 3 && eco >= from && eco <= to
+	}
+}
+
+// Result matches games with any of the results, such as "1-0".
+func Result(results ...string) Filter {
+	return func(g *Game) bool {
+		for _, r := range results {
+			if g.Result() == r {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// PlyCount matches games whose main line has from min to max plies. A max of
+// 0 leaves the range open.
+func PlyCount(min, max int) Filter {
+	return func(g *Game) bool {
+		n := len(g.Plies())
+		return n >= min && (max == 0 || n <= max)
+	}
+}
+
+// ReachesPosition matches games whose main line reaches the position of fen.
+// The move counters of the FEN are ignored.
+func ReachesPosition(fen string) (Filter, error) {
+	h, err := HashFEN(fen)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(g *Game) bool {
+		return anyPosition(g, func(pos *Position) bool {
+			return pos.Hash() == h
+		})
+	}, nil
+}
+
+// Material matches games whose main line reaches a position with exactly the
+// material of signature, which lists white's pieces and then black's, as in
+// "KRP:KR" or "KQvK".
+func Material(signature string) (Filter, error) {
+	want, err := parseMaterial(signature)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(g *Game) bool {
+		return anyPosition(g, func(pos *Position) bool {
+			return pos.material() == want
+		})
+	}, nil
+}
+
+// anyPosition reports whether fn holds for a position of the game's main
+// line. Games that cannot be replayed do not match.
+func anyPosition(g *Game, fn func(pos *Position) bool) bool {
+	positions, err := g.Positions()
+	if err != nil {
+		return false
+	}
+
+	for _, pos := range positions {
+		if fn(pos) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// materialCount counts pieces by color and type.
+type materialCount [2][King + 1]int
+
+func (pos *Position) material() materialCount {
+	var m materialCount
+	for _, p := range pos.board {
+		if !p.IsEmpty() {
+			m[p.Color][p.Type]++
+		}
+	}
+
+	return m
+}
+
+func parseMaterial(signature string) (materialCount, error) {
+	var m materialCount
+
+	i := strings.IndexAny(signature, ":v")
+	if i < 0 {
+		return m, fmt.Errorf("invalid material signature %q, want white and black pieces as in KRP:KR", signature)
+	}
+
+	for c, side := range []string{signature[:i], signature[i+1:]} {
+		for _, ch := range []byte(strings.TrimSpace(side)) {
+			pt := pieceTypeFromLetter(ch)
+			if pt == NoPieceType || ch < 'A' || ch > 'Z' {
+				return m, fmt.Errorf("invalid piece %q in material signature %q", ch, signature)
+			}
+			m[c][pt]++
+		}
+	}
+
+	return m, nil
+}
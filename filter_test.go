@@ -0,0 +1,113 @@
+package pgn
+
+import "testing"
+
+const filterDatabase = `[Event "Open"]
+[Date "2023.05.10"]
+[White "Carlsen, Magnus"]
+[Black "Nakamura, Hikaru"]
+[WhiteElo "2850"]
+[BlackElo "2780"]
+[ECO "C65"]
+[Result "1-0"]
+
+1. e4 e5 2. Nf3 Nc6 3. Bb5 Nf6 1-0
+
+[Event "Club"]
+[Date "2024.01.??"]
+[White "Smith, John"]
+[Black "Carlsen, Magnus"]
+[WhiteElo "1900"]
+[BlackElo "2850"]
+[ECO "B20"]
+[Result "0-1"]
+
+1. e4 c5 0-1
+
+[Event "Endgame"]
+[Date "2024.06.01"]
+[White "Smith, John"]
+[Black "Doe, Jane"]
+[SetUp "1"]
+[FEN "4k3/8/8/8/8/8/4P3/4K2R w K - 0 1"]
+[Result "1/2-1/2"]
+
+1. Rh8+ Kd7 2. Rh7+ Ke6 3. Rh6+ Kf5 1/2-1/2
+`
+
+func filterEvents(t *testing.T, f Filter) []string {
+	t.Helper()
+
+	games, err := NewGames(filterDatabase)
+	if err != nil {
+		t.Fatalf("NewGames() error: %v", err)
+	}
+
+	events := []string{}
+	for _, g := range FilterGames(games, f) {
+		events = append(events, g.Event())
+	}
+
+	return events
+}
+
+func TestFilters(t *testing.T) {
+	reaches, err := ReachesPosition("r1bqkb1r/pppp1ppp/2n2n2/1B2p3/4P3/5N2/PPPP1PPP/RNBQK2R w KQkq - 4 4")
+	if err != nil {
+		t.Fatalf("ReachesPosition() error: %v", err)
+	}
+
+	material, err := Material("KRP:K")
+	if err != nil {
+		t.Fatalf("Material() error: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		filter   Filter
+		expected []string
+	}{
+		{"player either color", Player("carlsen"), []string{"Open", "Club"}},
+		{"elo range", EloRange(2700, 0), []string{"Open"}},
+		{"elo range with max", EloRange(1800, 2800), []string{}},
+		{"date range", DateRange(Date{Year: 2024}, Date{}), []string{"Club", "Endgame"}},
+		{"date range to a year", DateRange(Date{}, Date{Year: 2023}), []string{"Open"}},
+		{"eco range", ECORange("B00", "B99"), []string{"Club"}},
+		{"result", Result("1-0", "1/2-1/2"), []string{"Open", "Endgame"}},
+		{"ply count", PlyCount(0, 4), []string{"Club"}},
+		{"ply count at least", PlyCount(6, 0), []string{"Open", "Endgame"}},
+		{"position", reaches, []string{"Open"}},
+		{"material", material, []string{"Endgame"}},
+		{"and", And(Player("Smith"), Not(Result("0-1"))), []string{"Endgame"}},
+		{"or", Or(ECORange("C00", "C99"), Result("0-1")), []string{"Open", "Club"}},
+		{"no filters", And(), []string{"Open", "Club", "Endgame"}},
+	}
+
+	for _, tt := range tests {
+		got := filterEvents(t, tt.filter)
+		if len(got) != len(tt.expected) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.expected)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.expected[i] {
+				t.Errorf("%s: got %v, want %v", tt.name, got, tt.expected)
+				break
+			}
+		}
+	}
+}
+
+func TestMaterialSignature(t *testing.T) {
+	for _, signature := range []string{"KQvK", "KRP : KR", "K:K"} {
+		if _, err := Material(signature); err != nil {
+			t.Errorf("Material(%q) error: %v", signature, err)
+		}
+	}
+
+	for _, signature := range []string{"KQK", "KX:K", "kq:k"} {
+		if _, err := Material(signature); err == nil {
+			t.Errorf("Material(%q) did not fail", signature)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package pgn
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+	"io"
+	"time"
+)
+
+// GIFOptions configures animated replays. The board is drawn as by
+// DiagramOptions.
+type GIFOptions struct {
+	DiagramOptions
+	// Delay is how long each ply is shown, and FinalDelay how long the final
+	// position is shown before the replay loops.
+	Delay      time.Duration
+	FinalDelay time.Duration
+}
+
+var DefaultGIFOptions = GIFOptions{
+	DiagramOptions: DefaultDiagramOptions,
+	Delay:          time.Second,
+	FinalDelay:     3 * time.Second,
+}
+
+// WriteGIF writes the main line of the game as a looping animated GIF, one
+// frame for the starting position and one for every ply.
+func (g *Game) WriteGIF(w io.Writer, opts GIFOptions) error {
+	opts.DiagramOptions = opts.DiagramOptions.withDefaults()
+	if opts.Delay <= 0 {
+		opts.Delay = DefaultGIFOptions.Delay
+	}
+	if opts.FinalDelay <= 0 {
+		opts.FinalDelay = opts.Delay
+	}
+
+	frames, err := g.frames(opts.DiagramOptions)
+	if err != nil {
+		return err
+	}
+
+	anim := &gif.GIF{}
+	pal, exact := framePalette(frames)
+
+	for i, frame := range frames {
+		paletted := image.NewPaletted(frame.Bounds(), pal)
+		if exact {
+			draw.Draw(paletted, frame.Bounds(), frame, image.Point{}, draw.Src)
+		} else {
+			draw.FloydSteinberg.Draw(paletted, frame.Bounds(), frame, image.Point{})
+		}
+
+		delay := opts.Delay
+		if i == len(frames)-1 {
+			delay = opts.FinalDelay
+		}
+
+		anim.Image = append(anim.Image, paletted)
+		anim.Delay = append(anim.Delay, int(delay/(10*time.Millisecond)))
+	}
+
+	if err := gif.EncodeAll(w, anim); err != nil {
+		return fmt.Errorf("encoding GIF: %v", err)
+	}
+
+	return nil
+}
+
+// frames draws every position of the main line, replaying the game once.
+func (g *Game) frames(opts DiagramOptions) ([]*image.RGBA, error) {
+	positions, err := g.Positions()
+	if err != nil {
+		return nil, err
+	}
+	plies := g.Plies()
+
+	frames := make([]*image.RGBA, 0, len(positions))
+	for i, pos := range positions {
+		marks := diagramMarks{from: NoSquare, to: NoSquare}
+
+		switch {
+		case opts.Marks && i == 0:
+			marks.arrows, marks.highlights = g.Marks(0)
+		case opts.Marks:
+			marks.arrows, marks.highlights = plies[i-1].Arrows(), plies[i-1].Highlights()
+		}
+
+		if opts.LastMove && i > 0 {
+			m, err := positions[i-1].parseSAN(plies[i-1].SAN)
+			if err != nil {
+				return nil, err
+			}
+			marks.from, marks.to = positions[i-1].boardMove(m)
+		}
+
+		frames = append(frames, pos.diagram(opts, marks).rasterize(opts.Size))
+	}
+
+	return frames, nil
+}
+
+// framePalette returns the colors used by the frames. Diagrams are drawn
+// without antialiasing and rarely need more than a few dozen colors; should
+// they exceed a GIF palette, the Plan 9 palette is returned and exact is
+// false.
+func framePalette(frames []*image.RGBA) (pal color.Palette, exact bool) {
+	seen := map[color.RGBA]bool{}
+
+	for _, frame := range frames {
+		for i := 0; i < len(frame.Pix); i += 4 {
+			c := color.RGBA{frame.Pix[i], frame.Pix[i+1], frame.Pix[i+2], frame.Pix[i+3]}
+			if seen[c] {
+				continue
+			}
+			if len(pal) == 256 {
+				return palette.Plan9, false
+			}
+			seen[c] = true
+			pal = append(pal, c)
+		}
+	}
+
+	return pal, true
+}
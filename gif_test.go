@@ -0,0 +1,81 @@
+package pgn
+
+import (
+	"bytes"
+	"image/gif"
+	"testing"
+	"time"
+)
+
+func TestWriteGIF(t *testing.T) {
+	game, err := New("[Result \"*\"]\n\n1. e4 {[%cal Gg1f3]} e5 2. Nf3 Nc6 *")
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+
+	opts := DefaultGIFOptions
+	opts.Size = 160
+	opts.Flip = true
+	opts.Delay = 500 * time.Millisecond
+
+	var buf bytes.Buffer
+	if err := game.WriteGIF(&buf, opts); err != nil {
+		t.Fatalf("WriteGIF() error: %v", err)
+	}
+
+	anim, err := gif.DecodeAll(&buf)
+	if err != nil {
+		t.Fatalf("DecodeAll() error: %v", err)
+	}
+
+	if len(anim.Image) != 5 {
+		t.Fatalf("WriteGIF() wrote %d frames, want 5", len(anim.Image))
+	}
+
+	expectedDelays := []int{50, 50, 50, 50, 300}
+	for i, delay := range anim.Delay {
+		if delay != expectedDelays[i] {
+			t.Errorf("frame %d delay = %d, want %d", i, delay, expectedDelays[i])
+		}
+	}
+
+	if b := anim.Image[0].Bounds(); b.Dx() != 160 || b.Dy() != 160 {
+		t.Errorf("frame size = %v, want 160x160", b)
+	}
+
+	// Frames match the static images pixel for pixel.
+	for _, ply := range []int{0, 1, 4} {
+		img, err := game.Image(ply, opts.DiagramOptions)
+		if err != nil {
+			t.Fatalf("Image(%d) error: %v", ply, err)
+		}
+
+		frame := anim.Image[ply]
+		for y := 0; y < 160; y += 7 {
+			for x := 0; x < 160; x += 7 {
+				r1, g1, b1, _ := img.At(x, y).RGBA()
+				r2, g2, b2, _ := frame.At(x, y).RGBA()
+				if r1 != r2 || g1 != g2 || b1 != b2 {
+					t.Fatalf("frame %d differs from Image() at %d,%d", ply, x, y)
+				}
+			}
+		}
+	}
+}
+
+func TestPositionImage(t *testing.T) {
+	img := StartingPosition().Image(DiagramOptions{Size: 80})
+
+	// a1 is a dark square in the bottom left corner, h1 a light one.
+	if got := img.RGBAAt(1, 79); got != BrownTheme.Dark {
+		t.Errorf("a1 corner = %v, want %v", got, BrownTheme.Dark)
+	}
+	if got := img.RGBAAt(78, 79); got != BrownTheme.Light {
+		t.Errorf("h1 corner = %v, want %v", got, BrownTheme.Light)
+	}
+
+	// The white king stands on e1.
+	if got := img.RGBAAt(45, 75); got != BrownTheme.WhitePiece && got != BrownTheme.Outline {
+		t.Errorf("e1 = %v, want a white piece", got)
+	}
+}
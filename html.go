@@ -0,0 +1,361 @@
+package pgn
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"image/color"
+	"strings"
+)
+
+// HTMLOptions configures the HTML game viewer. The board is drawn as by
+// DiagramOptions, with Size its width in pixels.
+type HTMLOptions struct {
+	DiagramOptions
+	// Title defaults to "White - Black".
+	Title string
+}
+
+var DefaultHTMLOptions = HTMLOptions{DiagramOptions: DefaultDiagramOptions}
+
+// htmlNode is a position of the viewer. Prev and Next link the plies of the
+// line it belongs to, and are -1 at either end.
+type htmlNode struct {
+	Board      string   `json:"board"`
+	LastMove   []string `json:"lastMove,omitempty"`
+	Arrows     []string `json:"arrows,omitempty"`
+	Highlights []string `json:"highlights,omitempty"`
+	Prev       int      `json:"prev"`
+	Next       int      `json:"next"`
+}
+
+type htmlViewer struct {
+	opts  HTMLOptions
+	nodes []htmlNode
+	moves strings.Builder
+}
+
+// HTML returns a standalone web page showing the game: a move list with its
+// comments, NAGs and variations, and a board that follows the clicked move.
+// Styles and scripts are inline, so the page works offline as a single file.
+func (g *Game) HTML(opts HTMLOptions) (string, error) {
+	opts.DiagramOptions = opts.DiagramOptions.withDefaults()
+	if opts.Title == "" {
+		opts.Title = g.White() + " - " + g.Black()
+	}
+
+	positions, err := g.Positions()
+	if err != nil {
+		return "", err
+	}
+
+	v := &htmlViewer{opts: opts}
+	arrows, highlights := g.Marks(0)
+	v.addNode(positions[0], -1, NoSquare, NoSquare, arrows, highlights)
+
+	if err := v.line(positions[0], 0, g.comments, g.Plies()); err != nil {
+		return "", err
+	}
+	fmt.Fprintf(&v.moves, `<span class="result">%s</span>`, html.EscapeString(g.Result()))
+
+	return v.page(g)
+}
+
+func (v *htmlViewer) addNode(pos *Position, prev int, from, to Square, arrows []Arrow, highlights []Highlight) int {
+	node := htmlNode{Board: pos.ranks(false), Prev: prev, Next: -1}
+
+	if v.opts.LastMove && to != NoSquare {
+		if from != NoSquare {
+			node.LastMove = append(node.LastMove, from.String())
+		}
+		node.LastMove = append(node.LastMove, to.String())
+	}
+
+	if v.opts.Marks {
+		for _, a := range arrows {
+			node.Arrows = append(node.Arrows, a.String())
+		}
+		for _, h := range highlights {
+			node.Highlights = append(node.Highlights, h.String())
+		}
+	}
+
+	id := len(v.nodes)
+	v.nodes = append(v.nodes, node)
+	if prev >= 0 && v.nodes[prev].Next == -1 {
+		v.nodes[prev].Next = id
+	}
+
+	return id
+}
+
+// line writes the plies of a line, played from pos after the node parent,
+// to the move list. Variations are written as nested blocks.
+func (v *htmlViewer) line(pos *Position, parent int, comments []string, plies []Ply) error {
+	v.comments(comments)
+
+	needNumber := true
+	for _, p := range plies {
+		m, err := pos.parseSAN(p.SAN)
+		if err != nil {
+			return err
+		}
+
+		next := pos.play(m)
+		from, to := pos.boardMove(m)
+		id := v.addNode(next, parent, from, to, p.Arrows(), p.Highlights())
+
+		switch {
+		case p.Color == White:
+			fmt.Fprintf(&v.moves, `<span class="number">%d.</span>`, p.MoveNumber)
+		case needNumber:
+			fmt.Fprintf(&v.moves, `<span class="number">%d...</span>`, p.MoveNumber)
+		}
+
+		fmt.Fprintf(&v.moves, `<span class="move" data-node="%d">%s`, id, html.EscapeString(p.SAN))
+		for _, nag := range p.Annotations {
+			fmt.Fprintf(&v.moves, `<span class="nag">%s</span>`, html.EscapeString(nagGlyph(nag)))
+		}
+		v.moves.WriteString("</span>\n")
+
+		v.comments(p.Comments)
+
+		for _, variation := range p.Variations {
+			v.moves.WriteString(`<div class="variation">`)
+			if err := v.line(pos, parent, variation.Comments, variation.Plies()); err != nil {
+				return err
+			}
+			v.moves.WriteString("</div>\n")
+		}
+
+		needNumber = len(p.Comments) > 0 || len(p.Variations) > 0
+		pos, parent = next, id
+	}
+
+	return nil
+}
+
+// comments writes comments without their embedded commands, which the board
+// shows instead.
+func (v *htmlViewer) comments(comments []string) {
+	for _, c := range comments {
+		if text := StripCommands(c); text != "" {
+			fmt.Fprintf(&v.moves, `<span class="comment">%s</span>`+"\n", html.EscapeString(text))
+		}
+	}
+}
+
+func (v *htmlViewer) page(g *Game) (string, error) {
+	theme := v.opts.Theme
+	marks := map[string]string{}
+	for c, rgba := range theme.Arrows {
+		marks[string(c)] = cssColor(rgba)
+	}
+
+	data, err := json.Marshal(map[string]any{
+		"size":        v.opts.Size,
+		"flip":        v.opts.Flip,
+		"coordinates": v.opts.Coordinates,
+		"light":       cssColor(theme.Light),
+		"dark":        cssColor(theme.Dark),
+		"lastMove":    cssColor(theme.LastMove),
+		"labels":      []string{cssColor(theme.Coordinates[0]), cssColor(theme.Coordinates[1])},
+		"marks":       marks,
+		"nodes":       v.nodes,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	details := []string{}
+	for _, tag := range []string{"Event", "Site", "Date", "Round", "Result"} {
+		if value := g.GetTag(tag); value != "" && value != "?" && value != "????.??.??" {
+			details = append(details, html.EscapeString(value))
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n")
+	fmt.Fprintf(&sb, "<title>%s</title>\n<style>%s</style>\n</head>\n<body>\n", html.EscapeString(v.opts.Title), htmlViewerCSS)
+	fmt.Fprintf(&sb, "<h1>%s</h1>\n<p class=\"details\">%s</p>\n", html.EscapeString(v.opts.Title), strings.Join(details, " · "))
+	sb.WriteString(v.pieceSymbols())
+	sb.WriteString("<div class=\"viewer\">\n<div>\n<div id=\"board\"></div>\n")
+	sb.WriteString("<div class=\"controls\"><button data-go=\"first\">&#x23EE;</button><button data-go=\"prev\">&#x25C0;</button><button data-go=\"next\">&#x25B6;</button><button data-go=\"last\">&#x23ED;</button></div>\n</div>\n")
+	fmt.Fprintf(&sb, "<div class=\"moves\">\n%s\n</div>\n</div>\n", v.moves.String())
+	fmt.Fprintf(&sb, "<script type=\"application/json\" id=\"game-data\">%s</script>\n", data)
+	fmt.Fprintf(&sb, "<script>%s</script>\n</body>\n</html>\n", htmlViewerJS)
+
+	return sb.String(), nil
+}
+
+// pieceSymbols defines the pieces of the piece set as SVG symbols named by
+// color and letter, such as "wK", for the board to use.
+func (v *htmlViewer) pieceSymbols() string {
+	var sb strings.Builder
+	sb.WriteString(`<svg xmlns="http://www.w3.org/2000/svg" style="display:none">` + "\n")
+
+	theme, pieces := v.opts.Theme, v.opts.Pieces
+	for _, c := range []Color{White, Black} {
+		fill, prefix := theme.WhitePiece, "w"
+		if c == Black {
+			fill, prefix = theme.BlackPiece, "b"
+		}
+
+		for pt := Pawn; pt <= King; pt++ {
+			fmt.Fprintf(&sb, `<symbol id="%s%s" viewBox="0 0 45 45">`, prefix, pt.Letter())
+			for _, polygon := range pieces.shapes[pt] {
+				fmt.Fprintf(&sb, `<path d="%s"%s%s stroke-width="%s" stroke-linejoin="round"/>`,
+					svgContour(polygon), svgPaint("fill", fill), svgPaint("stroke", theme.Outline), svgNumber(pieces.outline))
+			}
+			sb.WriteString("</symbol>\n")
+		}
+	}
+
+	sb.WriteString("</svg>\n")
+	return sb.String()
+}
+
+func cssColor(c color.RGBA) string {
+	return fmt.Sprintf("rgba(%d,%d,%d,%s)", c.R, c.G, c.B, svgNumber(float64(c.A)/0xff))
+}
+
+const htmlViewerCSS = `
+body { font-family: sans-serif; margin: 2em; color: #222; }
+h1 { font-size: 1.4em; margin: 0; }
+.details { color: #666; margin: 0.3em 0 1em; }
+.viewer { display: flex; flex-wrap: wrap; gap: 2em; align-items: flex-start; }
+.controls { display: flex; justify-content: center; gap: 0.5em; margin-top: 0.5em; }
+.controls button { font-size: 1.1em; padding: 0.2em 0.8em; cursor: pointer; }
+.moves { flex: 1; min-width: 18em; max-height: 80vh; overflow-y: auto; line-height: 1.7; }
+.number { color: #888; margin-right: 0.2em; }
+.move { cursor: pointer; padding: 0.1em 0.25em; border-radius: 3px; margin-right: 0.2em; }
+.move:hover { background: #e4ecf4; }
+.move.current { background: #3b76c4; color: #fff; }
+.nag { margin-left: 0.1em; }
+.comment { color: #2a6f2a; margin-right: 0.3em; }
+.variation { margin-left: 1.5em; padding-left: 0.6em; border-left: 2px solid #ddd; color: #555; }
+.result { font-weight: bold; margin-left: 0.3em; }
+`
+
+const htmlViewerJS = `
+(function () {
+  var data = JSON.parse(document.getElementById("game-data").textContent);
+  var board = document.getElementById("board");
+  var moves = document.querySelectorAll(".move");
+  var current = 0;
+
+  function place(name) {
+    var file = name.charCodeAt(0) - 97, rank = name.charCodeAt(1) - 49;
+    return data.flip ? [(7 - file) * 45, rank * 45] : [file * 45, (7 - rank) * 45];
+  }
+
+  function arrow(from, to) {
+    var dx = to[0] - from[0], dy = to[1] - from[1], length = Math.sqrt(dx * dx + dy * dy);
+    var ux = dx / length, uy = dy / length, nx = -uy, ny = ux;
+    var bx = to[0] - ux * 18, by = to[1] - uy * 18;
+    var side = function (x, y, w) { return (x + nx * w).toFixed(2) + "," + (y + ny * w).toFixed(2); };
+    return [side(from[0], from[1], 4.5), side(bx, by, 4.5), side(bx, by, 11), side(to[0], to[1], 0),
+      side(bx, by, -11), side(bx, by, -4.5), side(from[0], from[1], -4.5)].join(" ");
+  }
+
+  function render() {
+    var node = data.nodes[current];
+    var svg = ['<svg xmlns="http://www.w3.org/2000/svg" width="' + data.size + '" height="' + data.size + '" viewBox="0 0 360 360">'];
+
+    for (var row = 0; row < 8; row++) {
+      for (var col = 0; col < 8; col++) {
+        svg.push('<rect x="' + col * 45 + '" y="' + row * 45 + '" width="45" height="45" fill="' + ((row + col) % 2 === 0 ? data.light : data.dark) + '"/>');
+      }
+    }
+
+    (node.lastMove || []).forEach(function (sq) {
+      var p = place(sq);
+      svg.push('<rect x="' + p[0] + '" y="' + p[1] + '" width="45" height="45" fill="' + data.lastMove + '"/>');
+    });
+
+    (node.highlights || []).forEach(function (h) {
+      var p = place(h.slice(1));
+      svg.push('<circle cx="' + (p[0] + 22.5) + '" cy="' + (p[1] + 22.5) + '" r="19.58" fill="none" stroke-width="3.15" stroke="' + data.marks[h[0]] + '"/>');
+    });
+
+    if (data.coordinates) {
+      for (var i = 0; i < 8; i++) {
+        var file = data.flip ? 7 - i : i, rank = data.flip ? i : 7 - i;
+        svg.push('<text x="' + (i * 45 + 37) + '" y="358" font-size="10" font-weight="bold" fill="' + data.labels[i % 2 === 0 ? 1 : 0] + '">' + String.fromCharCode(97 + file) + '</text>');
+        svg.push('<text x="2" y="' + (i * 45 + 11) + '" font-size="10" font-weight="bold" fill="' + data.labels[i % 2 === 0 ? 0 : 1] + '">' + (rank + 1) + '</text>');
+      }
+    }
+
+    node.board.split("/").forEach(function (rankText, r) {
+      var f = 0;
+      for (var k = 0; k < rankText.length; k++) {
+        var ch = rankText[k];
+        if (ch >= "1" && ch <= "8") {
+          f += parseInt(ch, 10);
+          continue;
+        }
+        var p = place(String.fromCharCode(97 + f) + (8 - r));
+        var id = (ch === ch.toUpperCase() ? "w" : "b") + ch.toUpperCase();
+        svg.push('<use href="#' + id + '" x="' + p[0] + '" y="' + p[1] + '" width="45" height="45"/>');
+        f++;
+      }
+    });
+
+    (node.arrows || []).forEach(function (a) {
+      var from = place(a.slice(1, 3)), to = place(a.slice(3, 5));
+      if (from[0] === to[0] && from[1] === to[1]) {
+        return;
+      }
+      svg.push('<polygon points="' + arrow([from[0] + 22.5, from[1] + 22.5], [to[0] + 22.5, to[1] + 22.5]) + '" fill="' + data.marks[a[0]] + '"/>');
+    });
+
+    svg.push("</svg>");
+    board.innerHTML = svg.join("");
+
+    moves.forEach(function (m) {
+      var selected = parseInt(m.dataset.node, 10) === current;
+      m.classList.toggle("current", selected);
+      if (selected) {
+        m.scrollIntoView({ block: "nearest" });
+      }
+    });
+  }
+
+  function go(where) {
+    var node = data.nodes[current];
+    if (where === "first") {
+      current = 0;
+    } else if (where === "prev" && node.prev >= 0) {
+      current = node.prev;
+    } else if (where === "next" && node.next >= 0) {
+      current = node.next;
+    } else if (where === "last") {
+      while (data.nodes[current].next >= 0) {
+        current = data.nodes[current].next;
+      }
+    }
+    render();
+  }
+
+  moves.forEach(function (m) {
+    m.addEventListener("click", function () {
+      current = parseInt(m.dataset.node, 10);
+      render();
+    });
+  });
+
+  document.querySelectorAll(".controls button").forEach(function (b) {
+    b.addEventListener("click", function () { go(b.dataset.go); });
+  });
+
+  document.addEventListener("keydown", function (e) {
+    var keys = { ArrowLeft: "prev", ArrowRight: "next", Home: "first", End: "last" };
+    if (keys[e.key]) {
+      e.preventDefault();
+      go(keys[e.key]);
+    }
+  });
+
+  render();
+})();
+`
@@ -0,0 +1,123 @@
+package pgn
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestGameHTML(t *testing.T) {
+	game, err := New(`[White "Anderssen <A>"]
+[Black "Kieseritzky"]
+[Result "1-0"]
+
+{Start [%csl Ge4]} 1. e4! {Best [%cal Ge7e5]} e5 (1... c5 2. Nf3 (2. c3 d5) 2... d6) 2. Nf3 $14 Nc6 1-0`)
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+
+	page, err := game.HTML(DefaultHTMLOptions)
+	if err != nil {
+		t.Fatalf("HTML() error: %v", err)
+	}
+
+	checks := []string{
+		"<title>Anderssen &lt;A&gt; - Kieseritzky</title>",
+		`<span class="comment">Start</span>`,
+		`<span class="move" data-node="1">e4<span class="nag">!</span></span>`,
+		`<span class="number">1...</span><span class="move" data-node="2">e5</span>`,
+		`<div class="variation"><span class="number">1...</span><span class="move" data-node="3">c5</span>`,
+		`<div class="variation"><span class="number">2.</span><span class="move" data-node="5">c3</span>`,
+		`<span class="move" data-node="8">Nf3<span class="nag">⩲</span></span>`,
+		`<span class="result">1-0</span>`,
+		`<symbol id="bN" viewBox="0 0 45 45">`,
+	}
+	for _, check := range checks {
+		if !strings.Contains(page, check) {
+			t.Errorf("HTML() does not contain %q", check)
+		}
+	}
+
+	if strings.Contains(page, "[%cal") || strings.Contains(page, "<script src") || strings.Contains(page, "<link") {
+		t.Errorf("HTML() contains commands or external resources")
+	}
+
+	data := regexp.MustCompile(`(?s)<script type="application/json" id="game-data">(.*?)</script>`).FindStringSubmatch(page)
+	if data == nil {
+		t.Fatalf("HTML() has no game data")
+	}
+
+	var decoded struct {
+		Nodes []htmlNode `json:"nodes"`
+	}
+	if err := json.Unmarshal([]byte(data[1]), &decoded); err != nil {
+		t.Fatalf("game data is not JSON: %v", err)
+	}
+
+	nodes := decoded.Nodes
+	if len(nodes) != 10 {
+		t.Fatalf("HTML() has %d nodes, want 10", len(nodes))
+	}
+
+	// The main line continues with 1... e5, and 1... c5 branches from 1. e4.
+	if nodes[1].Next != 2 || nodes[3].Prev != 1 || nodes[4].Next != 7 || nodes[5].Prev != 3 {
+		t.Errorf("nodes are not linked by line: %+v", nodes)
+	}
+
+	if got := nodes[3].LastMove; len(got) != 2 || got[0] != "c7" || got[1] != "c5" {
+		t.Errorf("node 3 last move = %v, want [c7 c5]", got)
+	}
+	if got := nodes[0].Highlights; len(got) != 1 || got[0] != "Ge4" {
+		t.Errorf("node 0 highlights = %v, want [Ge4]", got)
+	}
+	if got := nodes[1].Arrows; len(got) != 1 || got[0] != "Ge7e5" {
+		t.Errorf("node 1 arrows = %v, want [Ge7e5]", got)
+	}
+	if nodes[9].Board != "r1bqkbnr/pppp1ppp/2n5/4p3/4P3/5N2/PPPP1PPP/RNBQKB1R" {
+		t.Errorf("node 9 board = %s", nodes[9].Board)
+	}
+}
+
+func TestCrazyhouseHTML(t *testing.T) {
+	game, err := New(`[Variant "Crazyhouse"]
+[FEN "4k3/8/8/8/8/8/8/4K2Q~[Pn] w - - 0 1"]
+[Result "*"]
+
+1. Qh8+ Kd7 2. P@e6+ *`)
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+
+	page, err := game.HTML(DefaultHTMLOptions)
+	if err != nil {
+		t.Fatalf("HTML() error: %v", err)
+	}
+
+	data := regexp.MustCompile(`(?s)<script type="application/json" id="game-data">(.*?)</script>`).FindStringSubmatch(page)
+	if data == nil {
+		t.Fatalf("HTML() has no game data")
+	}
+
+	var decoded struct {
+		Nodes []htmlNode `json:"nodes"`
+	}
+	if err := json.Unmarshal([]byte(data[1]), &decoded); err != nil {
+		t.Fatalf("game data is not JSON: %v", err)
+	}
+
+	boards := []string{
+		"4k3/8/8/8/8/8/8/4K2Q",
+		"4k2Q/8/8/8/8/8/8/4K3",
+		"7Q/3k4/8/8/8/8/8/4K3",
+		"7Q/3k4/4P3/8/8/8/8/4K3",
+	}
+	if len(decoded.Nodes) != len(boards) {
+		t.Fatalf("HTML() has %d nodes, want %d", len(decoded.Nodes), len(boards))
+	}
+	for i, node := range decoded.Nodes {
+		if node.Board != boards[i] {
+			t.Errorf("node %d board = %s, want %s", i, node.Board, boards[i])
+		}
+	}
+}
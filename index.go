@@ -0,0 +1,90 @@
+package pgn
+
+import "sort"
+
+// PositionHit records that a game reached a position after Ply plies.
+type PositionHit struct {
+	Game int
+	Ply  int
+}
+
+// PositionIndex maps position hashes to the games that reached them, so
+// positions can be found regardless of the move order that led to them.
+type PositionIndex struct {
+	games []*Game
+	hits  map[uint64][]PositionHit
+}
+
+func NewPositionIndex() *PositionIndex {
+	return &PositionIndex{
+		games: []*Game{},
+		hits:  map[uint64][]PositionHit{},
+	}
+}
+
+// Add replays the game and indexes every position of its mainline. It returns
+// the id under which the game is reported in hits.
+func (idx *PositionIndex) Add(g *Game) (int, error) {
+	positions, err := g.Positions()
+	if err != nil {
+		return -1, err
+	}
+
+	id := len(idx.games)
+	idx.games = append(idx.games, g)
+
+	for ply, pos := range positions {
+		h := pos.Hash()
+		idx.hits[h] = append(idx.hits[h], PositionHit{Game: id, Ply: ply})
+	}
+
+	return id, nil
+}
+
+func (idx *PositionIndex) Game(id int) *Game {
+	if id < 0 || id >= len(idx.games) {
+		return nil
+	}
+
+	return idx.games[id]
+}
+
+func (idx *PositionIndex) Len() int {
+	return len(idx.games)
+}
+
+func (idx *PositionIndex) LookupHash(h uint64) []PositionHit {
+	return idx.hits[h]
+}
+
+// Lookup returns every game and ply at which the position described by fen
+// was reached. The move counters of the FEN are ignored.
+func (idx *PositionIndex) Lookup(fen string) ([]PositionHit, error) {
+	h, err := HashFEN(fen)
+	if err != nil {
+		return nil, err
+	}
+
+	return idx.LookupHash(h), nil
+}
+
+// Games returns the ids of the games that reached the position described by
+// fen, each id once and in ascending order.
+func (idx *PositionIndex) Games(fen string) ([]int, error) {
+	hits, err := idx.Lookup(fen)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[int]bool{}
+	ids := []int{}
+	for _, hit := range hits {
+		if !seen[hit.Game] {
+			seen[hit.Game] = true
+			ids = append(ids, hit.Game)
+		}
+	}
+	sort.Ints(ids)
+
+	return ids, nil
+}
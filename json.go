@@ -0,0 +1,215 @@
+package pgn
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// jsonGame is the JSON schema of a game:
+//
+//	{
+//	  "tags": [{"name": "Event", "value": "Casual"}, ...],
+//	  "comments": ["comment before the first move", ...],
+//	  "moves": [ply, ...],
+//	  "result": "1-0"
+//	}
+//
+// Tags keep the order they were set in. Each ply is
+//
+//	{
+//	  "ply": 1,
+//	  "moveNumber": 1,
+//	  "color": "White",
+//	  "san": "e4",
+//	  "nags": [1],
+//	  "comments": ["[%clk 0:03:00] Best by test"],
+//	  "clock": 180,
+//	  "elapsed": 2.5,
+//	  "variations": [{"comments": [...], "moves": [ply, ...]}, ...]
+//	}
+//
+// where clock and elapsed are the seconds from the ply's %clk and %emt
+// annotations. Empty fields are omitted.
+type jsonGame struct {
+	Tags     []jsonTag `json:"tags"`
+	Comments []string  `json:"comments,omitempty"`
+	Moves    []jsonPly `json:"moves"`
+	Result   string    `json:"result"`
+}
+
+type jsonTag struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type jsonPly struct {
+	Ply        int             `json:"ply"`
+	MoveNumber int             `json:"moveNumber"`
+	Color      string          `json:"color"`
+	SAN        string          `json:"san"`
+	NAGs       []int           `json:"nags,omitempty"`
+	Comments   []string        `json:"comments,omitempty"`
+	Clock      *float64        `json:"clock,omitempty"`
+	Elapsed    *float64        `json:"elapsed,omitempty"`
+	Variations []jsonVariation `json:"variations,omitempty"`
+}
+
+type jsonVariation struct {
+	Comments []string  `json:"comments,omitempty"`
+	Moves    []jsonPly `json:"moves"`
+}
+
+// MarshalJSON encodes the game with the schema documented on jsonGame.
+func (g *Game) MarshalJSON() ([]byte, error) {
+	jg := jsonGame{
+		Tags:     []jsonTag{},
+		Comments: g.comments,
+		Moves:    jsonPlies(g.Plies()),
+		Result:   g.result,
+	}
+
+	for _, name := range g.tagOrder {
+		jg.Tags = append(jg.Tags, jsonTag{Name: name, Value: g.tags[name]})
+	}
+
+	return json.Marshal(jg)
+}
+
+func jsonPlies(plies []Ply) []jsonPly {
+	encoded := []jsonPly{}
+
+	for _, p := range plies {
+		jp := jsonPly{
+			Ply:        p.Index,
+			MoveNumber: p.MoveNumber,
+			Color:      p.Color.String(),
+			SAN:        p.SAN,
+			Comments:   p.Comments,
+		}
+
+		for _, a := range p.Annotations {
+			if n, err := strconv.Atoi(a); err == nil {
+				jp.NAGs = append(jp.NAGs, n)
+			}
+		}
+
+		if clock, ok := p.Clock(); ok {
+			seconds := clock.Seconds()
+			jp.Clock = &seconds
+		}
+		if elapsed, ok := p.ElapsedTime(); ok {
+			seconds := elapsed.Seconds()
+			jp.Elapsed = &seconds
+		}
+
+		for _, v := range p.Variations {
+			jp.Variations = append(jp.Variations, jsonVariation{
+				Comments: v.Comments,
+				Moves:    jsonPlies(v.Plies()),
+			})
+		}
+
+		encoded = append(encoded, jp)
+	}
+
+	return encoded
+}
+
+// UnmarshalJSON replaces the game with one decoded from the schema
+// documented on jsonGame. The "ply" field is ignored, but every ply needs
+// its "moveNumber", which places it in the game. A clock or elapsed
+// time overrides the %clk or %emt annotation in the ply's comments, so
+// that edits to either reach the game.
+func (g *Game) UnmarshalJSON(data []byte) error {
+	var jg jsonGame
+	if err := json.Unmarshal(data, &jg); err != nil {
+		return err
+	}
+
+	decoded := newGame()
+	decoded.comments = jg.Comments
+	decoded.result = jg.Result
+
+	for _, t := range jg.Tags {
+		decoded.SetTag(t.Name, t.Value)
+	}
+
+	mainline, err := decodePlies(jg.Moves)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range mainline.Moves {
+		if _, ok := decoded.moves[m.MoveNumber]; ok {
+			return fmt.Errorf("move %d appears twice in the main line", m.MoveNumber)
+		}
+		decoded.moves[m.MoveNumber] = m
+	}
+
+	*g = *decoded
+	return nil
+}
+
+func decodePlies(plies []jsonPly) (*Variation, error) {
+	line := &Variation{}
+
+	for i, jp := range plies {
+		if jp.SAN == "" {
+			return nil, fmt.Errorf("move %d has no SAN", i+1)
+		}
+		if jp.MoveNumber < 1 {
+			return nil, fmt.Errorf("move %d has no moveNumber", i+1)
+		}
+
+		annotations := []string{}
+		for _, n := range jp.NAGs {
+			annotations = append(annotations, strconv.Itoa(n))
+		}
+
+		comments := append([]string(nil), jp.Comments...)
+		if jp.Clock != nil {
+			comments = setCommand(comments, "clk", FormatClock(secondsDuration(*jp.Clock)))
+		}
+		if jp.Elapsed != nil {
+			comments = setCommand(comments, "emt", FormatClock(secondsDuration(*jp.Elapsed)))
+		}
+
+		variations := []*Variation{}
+		for _, jv := range jp.Variations {
+			v, err := decodePlies(jv.Moves)
+			if err != nil {
+				return nil, err
+			}
+			v.Comments = jv.Comments
+			variations = append(variations, v)
+		}
+		if len(variations) == 0 {
+			variations = nil
+		}
+
+		m := &Move{
+			MoveNumber:       jp.MoveNumber,
+			WhiteAnnotations: []string{},
+			BlackAnnotations: []string{},
+		}
+
+		switch jp.Color {
+		case "White":
+			m.MoveWhite, m.WhiteAnnotations, m.WhiteComments, m.WhiteVariations = jp.SAN, annotations, comments, variations
+		case "Black":
+			m.MoveBlack, m.BlackAnnotations, m.BlackComments, m.BlackVariations = jp.SAN, annotations, comments, variations
+		default:
+			return nil, fmt.Errorf("move %d has invalid color %q", i+1, jp.Color)
+		}
+
+		line.addMove(m)
+	}
+
+	return line, nil
+}
+
+func secondsDuration(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second))
+}
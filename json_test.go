@@ -0,0 +1,80 @@
+package pgn
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestGameJSON(t *testing.T) {
+	input := `[White "Carlsen"]
+[Black "Nakamura"]
+[Event "Blitz"]
+[Result "1-0"]
+
+{Start} 1. e4 {[%clk 0:03:00]} 1... c5!? {[%clk 0:02:58] [%emt 0:00:02.5]} (1... e5 2. Nf3 (2. f4)) 2. Nf3 $1 1-0`
+
+	game, err := New(input)
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+
+	data, err := json.Marshal(game)
+	if err != nil {
+		t.Fatalf("json.Marshal() error: %v", err)
+	}
+
+	expected := `{"tags":[{"name":"White","value":"Carlsen"},{"name":"Black","value":"Nakamura"},{"name":"Event","value":"Blitz"},{"name":"Result","value":"1-0"}],` +
+		`"comments":["Start"],"moves":[` +
+		`{"ply":1,"moveNumber":1,"color":"White","san":"e4","comments":["[%clk 0:03:00]"],"clock":180},` +
+		`{"ply":2,"moveNumber":1,"color":"Black","san":"c5","nags":[5],"comments":["[%clk 0:02:58] [%emt 0:00:02.5]"],"clock":178,"elapsed":2.5,` +
+		`"variations":[{"moves":[{"ply":1,"moveNumber":1,"color":"Black","san":"e5"},{"ply":2,"moveNumber":2,"color":"White","san":"Nf3",` +
+		`"variations":[{"moves":[{"ply":1,"moveNumber":2,"color":"White","san":"f4"}]}]}]}]},` +
+		`{"ply":3,"moveNumber":2,"color":"White","san":"Nf3","nags":[1]}],"result":"1-0"}`
+
+	if string(data) != expected {
+		t.Errorf("json.Marshal() =\n%s\nwant\n%s", data, expected)
+	}
+
+	var decoded Game
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("json.Unmarshal() error: %v", err)
+	}
+
+	if decoded.PGN() != game.PGN() {
+		t.Errorf("decoded game PGN =\n%s\nwant\n%s", decoded.PGN(), game.PGN())
+	}
+
+	if got := strings.Join(decoded.TagNames(), ","); got != "White,Black,Event,Result" {
+		t.Errorf("TagNames() = %s, want White,Black,Event,Result", got)
+	}
+}
+
+func TestGameJSONEdits(t *testing.T) {
+	data := `{"tags":[{"name":"Result","value":"*"}],"moves":[
+		{"moveNumber":1,"color":"White","san":"d4","comments":["[%clk 0:05:00] solid"],"clock":299.5},
+		{"moveNumber":1,"color":"Black","san":"Nf6","elapsed":3}
+	],"result":"*"}`
+
+	var game Game
+	if err := json.Unmarshal([]byte(data), &game); err != nil {
+		t.Fatalf("json.Unmarshal() error: %v", err)
+	}
+
+	expected := "[Result \"*\"]\n\n1. d4 {[%clk 0:04:59.5] solid} 1... Nf6 {[%emt 0:00:03]} *\n"
+	if got := game.PGN(); got != expected {
+		t.Errorf("PGN() = %q, want %q", got, expected)
+	}
+
+	for _, bad := range []string{
+		`{"moves":[{"moveNumber":1,"color":"Green","san":"e4"}]}`,
+		`{"moves":[{"moveNumber":1,"color":"White","san":""}]}`,
+		`{"moves":[{"color":"White","san":"e4"},{"color":"Black","san":"e5"}]}`,
+		`{"moves":[{"moveNumber":0,"color":"White","san":"e4"}]}`,
+		`{"moves":[{"moveNumber":1,"color":"White","san":"e4"},{"moveNumber":1,"color":"White","san":"d4"}]}`,
+	} {
+		if err := json.Unmarshal([]byte(bad), &game); err == nil {
+			t.Errorf("json.Unmarshal(%s) expected error", bad)
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package pgn
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// diagramNAG is the NAG that asks for a diagram after a move.
+const diagramNAG = "220"
+
+// LaTeXOptions configures LaTeX export.
+type LaTeXOptions struct {
+	// Diagrams lists the main line plies, counted from 1, after which a
+	// diagram is printed. Plies annotated with $220 always get one.
+	Diagrams []int
+	// Flip prints diagrams from black's side.
+	Flip bool
+}
+
+// LaTeX returns the game as LaTeX for the xskak package: a heading from the
+// players and event, the main line in \mainline and variations in
+// \variation, with comments as text and diagrams at the selected plies. It
+// is a fragment meant for a document that loads xskak; see WriteLaTeX.
+func (g *Game) LaTeX(opts LaTeXOptions) string {
+	w := &latexWriter{diagrams: map[int]bool{}, flip: opts.Flip}
+	for _, ply := range opts.Diagrams {
+		w.diagrams[ply] = true
+	}
+
+	fmt.Fprintf(&w.sb, "\\section*{%s -- %s}\n", escapeLaTeX(g.White()), escapeLaTeX(g.Black()))
+	if details := gameDetails(g); len(details) > 0 {
+		fmt.Fprintf(&w.sb, "\\textit{%s}\n", escapeLaTeX(strings.Join(details, ", ")))
+	}
+
+	keys := []string{}
+	for _, key := range []string{"White", "Black", "Event", "Site", "Date", "Round", "Result", "WhiteElo", "BlackElo"} {
+		if value := g.GetTag(key); value != "" {
+			keys = append(keys, fmt.Sprintf("%s={%s}", strings.ToLower(key), escapeLaTeX(value)))
+		}
+	}
+	if fen := g.GetTag("FEN"); fen != "" {
+		keys = append(keys, fmt.Sprintf("setfen={%s}", fen))
+	}
+	fmt.Fprintf(&w.sb, "\n\\newchessgame[%s]\n", strings.Join(keys, ","))
+
+	w.line("mainline", g.comments, g.Plies(), true)
+	fmt.Fprintf(&w.sb, "\\textbf{%s}\n", g.exportResult())
+
+	return w.sb.String()
+}
+
+// WriteLaTeX writes a complete LaTeX document holding the games, such as a
+// tournament bulletin.
+func WriteLaTeX(w io.Writer, games []*Game, opts LaTeXOptions) error {
+	header := "\\documentclass{article}\n\\usepackage[utf8]{inputenc}\n\\usepackage{xskak}\n\\begin{document}\n"
+	if _, err := io.WriteString(w, header); err != nil {
+		return err
+	}
+
+	for _, g := range games {
+		if _, err := io.WriteString(w, "\n"+g.LaTeX(opts)); err != nil {
+			return err
+		}
+	}
+
+	_, err := io.WriteString(w, "\n\\end{document}\n")
+	return err
+}
+
+type latexWriter struct {
+	sb       strings.Builder
+	diagrams map[int]bool
+	flip     bool
+}
+
+// line writes the plies of a line in the given xskak macro. The macro is
+// closed before comments, variations and diagrams, and the next chunk
+// starts with a move number, as xskak requires.
+func (w *latexWriter) line(macro string, comments []string, plies []Ply, mainline bool) {
+	var chunk []string
+	flush := func() {
+		if len(chunk) > 0 {
+			fmt.Fprintf(&w.sb, "\\%s{%s}\n", macro, strings.Join(chunk, " "))
+			chunk = nil
+		}
+	}
+
+	w.comments(comments)
+
+	for _, p := range plies {
+		switch {
+		case p.Color == White:
+			chunk = append(chunk, fmt.Sprintf("%d.", p.MoveNumber))
+		case len(chunk) == 0:
+			chunk = append(chunk, fmt.Sprintf("%d...", p.MoveNumber))
+		}
+
+		san, nags, diagram := p.SAN, []string{}, false
+		for _, nag := range p.Annotations {
+			switch {
+			case nag == diagramNAG:
+				diagram = true
+			case glyphNAGs[nagGlyph(nag)] == nag:
+				san += nagGlyph(nag)
+			default:
+				nags = append(nags, "$"+nag)
+			}
+		}
+		chunk = append(chunk, san)
+		chunk = append(chunk, nags...)
+
+		diagram = mainline && (diagram || w.diagrams[p.Index])
+		if !diagram && len(p.Comments) == 0 && len(p.Variations) == 0 {
+			continue
+		}
+
+		flush()
+
+		if diagram {
+			options := ""
+			if w.flip {
+				options = "[inverse]"
+			}
+			fmt.Fprintf(&w.sb, "\n\\begin{center}\n\\chessboard%s\n\\end{center}\n\n", options)
+		}
+
+		w.comments(p.Comments)
+
+		for _, v := range p.Variations {
+			w.sb.WriteString("(")
+			w.line("variation", v.Comments, v.Plies(), false)
+			w.sb.WriteString(")\n")
+		}
+	}
+
+	flush()
+}
+
+// comments writes comments as text without their embedded commands.
+func (w *latexWriter) comments(comments []string) {
+	for _, c := range comments {
+		if text := StripCommands(c); text != "" {
+			w.sb.WriteString(escapeLaTeX(text) + "\n")
+		}
+	}
+}
+
+// gameDetails returns the known event, site, date and round of a game.
+func gameDetails(g *Game) []string {
+	details := []string{}
+	for _, value := range []string{g.Event(), g.Site(), g.Date()} {
+		if value != "" && value != "?" && value != "????.??.??" {
+			details = append(details, value)
+		}
+	}
+
+	if round := g.Round(); round != "" && round != "?" && round != "-" {
+		details = append(details, "round "+round)
+	}
+
+	return details
+}
+
+var latexEscaper = strings.NewReplacer(
+	`\`, `\textbackslash{}`,
+	`&`, `\&`,
+	`%`, `\%`,
+	`$`, `\$`,
+	`#`, `\#`,
+	`_`, `\_`,
+	`{`, `\{`,
+	`}`, `\}`,
+	`~`, `\textasciitilde{}`,
+	`^`, `\textasciicircum{}`,
+)
+
+func escapeLaTeX(s string) string {
+	return latexEscaper.Replace(s)
+}
@@ -0,0 +1,64 @@
+package pgn
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestGameLaTeX(t *testing.T) {
+	game, err := New(`[Event "Club Championship"]
+[Site "Paris & Co"]
+[Date "2024.03.01"]
+[Round "2"]
+[White "Dupont"]
+[Black "Martin"]
+[Result "1-0"]
+
+1. e4 e5 2. Nf3 Nc6 3. Bb5 $220 {The Ruy Lopez, 100% sound.} a6 (3... Nf6 4. O-O) 4. Ba4 $14 Nf6?! 1-0`)
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+
+	expected := `\section*{Dupont -- Martin}
+\textit{Club Championship, Paris \& Co, 2024.03.01, round 2}
+
+\newchessgame[white={Dupont},black={Martin},event={Club Championship},site={Paris \& Co},date={2024.03.01},round={2},result={1-0}]
+\mainline{1. e4 e5 2. Nf3 Nc6 3. Bb5}
+
+\begin{center}
+\chessboard
+\end{center}
+
+The Ruy Lopez, 100\% sound.
+\mainline{3... a6}
+(\variation{3... Nf6 4. O-O}
+)
+\mainline{4. Ba4 $14 Nf6?!}
+
+\begin{center}
+\chessboard
+\end{center}
+
+\textbf{1-0}
+`
+	if got := game.LaTeX(LaTeXOptions{Diagrams: []int{8}}); got != expected {
+		t.Errorf("LaTeX() =\n%s\nwant\n%s", got, expected)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteLaTeX(&buf, []*Game{game, game}, LaTeXOptions{Flip: true}); err != nil {
+		t.Fatalf("WriteLaTeX() error: %v", err)
+	}
+
+	document := buf.String()
+	if !strings.HasPrefix(document, "\\documentclass{article}") || !strings.HasSuffix(document, "\\end{document}\n") {
+		t.Errorf("WriteLaTeX() is not a complete document:\n%s", document)
+	}
+	if got := strings.Count(document, "\\newchessgame"); got != 2 {
+		t.Errorf("WriteLaTeX() wrote %d games, want 2", got)
+	}
+	if !strings.Contains(document, "\\chessboard[inverse]") {
+		t.Errorf("WriteLaTeX() with Flip does not invert diagrams")
+	}
+}
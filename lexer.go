@@ -1,15 +1,20 @@
 package pgn
 
+import "strings"
+
 type lexer struct {
 	input        string
 	position     int  // Current position
 	readPosition int  // Position to read (after current position)
 	ch           byte // Current character under examination
+	line         int  // Line of the current character, counted from 1
+	lineStart    int  // Position of the first character of the line
 }
 
 func newLexer(input string) *lexer {
 	l := &lexer{
 		input: input,
+		line:  1,
 	}
 
 	l.readChar()
@@ -17,6 +22,11 @@ func newLexer(input string) *lexer {
 }
 
 func (l *lexer) readChar() {
+	if l.ch == '\n' {
+		l.line++
+		l.lineStart = l.readPosition
+	}
+
 	if l.readPosition >= len(l.input) {
 		l.ch = 0
 	} else {
@@ -27,11 +37,21 @@ func (l *lexer) readChar() {
 	l.readPosition += 1
 }
 
+// NextToken returns the next token with the line and column, counted from
+// 1, where it starts.
 func (l *lexer) NextToken() token {
-	var tok token
-
 	l.skipWhitespace()
 
+	line, column := l.line, l.position-l.lineStart+1
+	tok := l.readToken()
+	tok.Line, tok.Column = line, column
+
+	return tok
+}
+
+func (l *lexer) readToken() token {
+	var tok token
+
 	switch l.ch {
 	case '.':
 		tok = newToken(PERIOD, l.ch)
@@ -54,15 +74,23 @@ func (l *lexer) NextToken() token {
 	case '"':
 		tok.Type = STRING
 		tok.Literal = l.readString()
+	case '{':
+		tok.Type = COMMENT
+		tok.Literal = l.readBraceComment()
+	case ';':
+		tok.Type = COMMENT
+		tok.Literal = l.readLineComment()
 	case '$':
 		tok.Type = NAG
 		tok.Literal = l.readNAG()
+		return tok
 	case 0:
 		tok.Type = EOF
 		tok.Literal = ""
 	default:
-		if isLetter(l.ch) || isDigit(l.ch) {
+		if isLetter(l.ch) || isDigit(l.ch) || l.ch == '@' {
 			tok.Literal, tok.Type = l.readSymbolOrInteger()
+			return tok
 		} else {
 			tok = newToken(ILLEGAL, l.ch)
 		}
@@ -82,22 +110,49 @@ func (l *lexer) skipWhitespace() {
 	}
 }
 
+// readString reads a quoted tag value. A backslash escapes a following quote
+// or backslash.
 func (l *lexer) readString() string {
+	var sb strings.Builder
+	for {
+		l.readChar()
+		if l.ch == '\\' && (l.peekChar() == '"' || l.peekChar() == '\\') {
+			l.readChar()
+		} else if l.ch == '"' || l.ch == 0 {
+			break
+		}
+		sb.WriteByte(l.ch)
+	}
+
+	return sb.String()
+}
+
+func (l *lexer) readBraceComment() string {
 	position := l.position + 1
 	for {
 		l.readChar()
-		if l.ch == '"' || l.ch == 0 {
+		if l.ch == '}' || l.ch == 0 {
 			break
 		}
 	}
 
-	return l.input[position:l.position]
+	return strings.TrimSpace(l.input[position:l.position])
+}
+
+// readLineComment reads a comment that runs from ';' to the end of the line.
+func (l *lexer) readLineComment() string {
+	position := l.position + 1
+	for l.peekChar() != '\n' && l.peekChar() != 0 {
+		l.readChar()
+	}
+
+	return strings.TrimSpace(l.input[position:l.readPosition])
 }
 
 func (l *lexer) readNAG() string {
-  position := l.position + 1
+	position := l.position + 1
 
-  l.readChar()
+	l.readChar()
 
 	for isDigit(l.ch) {
 		l.readChar()
@@ -107,29 +162,23 @@ func (l *lexer) readNAG() string {
 }
 
 func (l *lexer) readSymbolOrInteger() (string, tokenType) {
-	flag := false
 	position := l.position
 
 	for isDigit(l.ch) || isLetter(l.ch) || isSpecialChar(l.ch) {
-		if l.peekChar() == '.' || l.peekChar() == '*' || l.peekChar() == '$' {
-			flag = true
-			break
-		}
-
 		l.readChar()
 	}
 
-	var tokenLiteral string
-
-	if flag {
-		tokenLiteral = l.input[position:l.readPosition]
-	} else {
-		tokenLiteral = l.input[position:l.position]
+	// An en passant capture may be marked "exd6e.p.", whose dots would
+	// otherwise end the move.
+	if l.position > position && l.input[l.position-1] == 'e' && strings.HasPrefix(l.input[l.position:], ".p.") {
+		for range 3 {
+			l.readChar()
+		}
 	}
 
-	isInteger := isDigitsOnly(tokenLiteral)
+	tokenLiteral := l.input[position:l.position]
 
-	if isInteger {
+	if isDigitsOnly(tokenLiteral) {
 		return tokenLiteral, INTEGER
 	}
 
@@ -246,3 +246,149 @@ func TestNextToken(t *testing.T) {
 		}
 	}
 }
+
+func TestDropTokens(t *testing.T) {
+	input := `4. P@e6 fxe6 5. N@f3 @e4`
+
+	tests := []struct {
+		expectedType    tokenType
+		expectedLiteral string
+	}{
+		{INTEGER, "4"},
+		{PERIOD, "."},
+		{SYMBOL, "P@e6"},
+		{SYMBOL, "fxe6"},
+		{INTEGER, "5"},
+		{PERIOD, "."},
+		{SYMBOL, "N@f3"},
+		{SYMBOL, "@e4"},
+		{EOF, ""},
+	}
+
+	l := newLexer(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests [%d] -- tokentype wrong. expected=%q, got=%q\n", i, tt.expectedType, tok.Type)
+		}
+
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests [%d] -- literal wrong. expected=%q, got=%q\n", i, tt.expectedLiteral, tok.Literal)
+		}
+	}
+}
+
+func TestEnPassantTokens(t *testing.T) {
+	input := `3. exd6e.p. Nc6 4. Ne2 e5.`
+
+	tests := []struct {
+		expectedType    tokenType
+		expectedLiteral string
+	}{
+		{INTEGER, "3"},
+		{PERIOD, "."},
+		{SYMBOL, "exd6e.p."},
+		{SYMBOL, "Nc6"},
+		{INTEGER, "4"},
+		{PERIOD, "."},
+		{SYMBOL, "Ne2"},
+		{SYMBOL, "e5"},
+		{PERIOD, "."},
+		{EOF, ""},
+	}
+
+	l := newLexer(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests [%d] -- tokentype wrong. expected=%q, got=%q\n", i, tt.expectedType, tok.Type)
+		}
+
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests [%d] -- literal wrong. expected=%q, got=%q\n", i, tt.expectedLiteral, tok.Literal)
+		}
+	}
+}
+
+func TestCommentTokens(t *testing.T) {
+	input := `1. e4!? { [%clk 0:03:00] } e5?; main line
+2. Nf3 $1 (2. f4)`
+
+	tests := []struct {
+		expectedType    tokenType
+		expectedLiteral string
+	}{
+		{INTEGER, "1"},
+		{PERIOD, "."},
+		{SYMBOL, "e4!?"},
+		{COMMENT, "[%clk 0:03:00]"},
+		{SYMBOL, "e5?"},
+		{COMMENT, "main line"},
+		{INTEGER, "2"},
+		{PERIOD, "."},
+		{SYMBOL, "Nf3"},
+		{NAG, "1"},
+		{LPAREN, "("},
+		{INTEGER, "2"},
+		{PERIOD, "."},
+		{SYMBOL, "f4"},
+		{RPAREN, ")"},
+		{EOF, ""},
+	}
+
+	l := newLexer(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests [%d] -- tokentype wrong. expected=%q, got=%q\n", i, tt.expectedType, tok.Type)
+		}
+
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests [%d] -- literal wrong. expected=%q, got=%q\n", i, tt.expectedLiteral, tok.Literal)
+		}
+	}
+}
+
+func TestTokenPositions(t *testing.T) {
+	input := "[Event \"Test\"]\n\n1. e4 {two\nlines} e5\n\t2. Nf3 $1"
+
+	tests := []struct {
+		expectedLiteral string
+		expectedLine    int
+		expectedColumn  int
+	}{
+		{"[", 1, 1},
+		{"Event", 1, 2},
+		{"Test", 1, 8},
+		{"]", 1, 14},
+		{"1", 3, 1},
+		{".", 3, 2},
+		{"e4", 3, 4},
+		{"two\nlines", 3, 7},
+		{"e5", 4, 8},
+		{"2", 5, 2},
+		{".", 5, 3},
+		{"Nf3", 5, 5},
+		{"1", 5, 9},
+	}
+
+	l := newLexer(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests [%d] -- literal wrong. expected=%q, got=%q\n", i, tt.expectedLiteral, tok.Literal)
+		}
+
+		if tok.Line != tt.expectedLine || tok.Column != tt.expectedColumn {
+			t.Errorf("tests [%d] -- %q at %d:%d, expected %d:%d\n", i, tok.Literal, tok.Line, tok.Column, tt.expectedLine, tt.expectedColumn)
+		}
+	}
+}
@@ -0,0 +1,331 @@
+package pgn
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// lichessGame is a game as exported by the lichess API in NDJSON, with
+// clocks and evaluations requested.
+type lichessGame struct {
+	ID        string `json:"id"`
+	Rated     bool   `json:"rated"`
+	Variant   string `json:"variant"`
+	Speed     string `json:"speed"`
+	CreatedAt int64  `json:"createdAt"`
+	Status    string `json:"status"`
+	Winner    string `json:"winner"`
+	Players   struct {
+		White lichessPlayer `json:"white"`
+		Black lichessPlayer `json:"black"`
+	} `json:"players"`
+	Opening *struct {
+		ECO  string `json:"eco"`
+		Name string `json:"name"`
+	} `json:"opening"`
+	InitialFen string `json:"initialFen"`
+	Moves      string `json:"moves"`
+	// Clocks holds the mover's remaining time after each ply in
+	// centiseconds.
+	Clocks []int `json:"clocks"`
+	Clock  *struct {
+		Initial   int `json:"initial"`
+		Increment int `json:"increment"`
+	} `json:"clock"`
+	DaysPerTurn int               `json:"daysPerTurn"`
+	Analysis    []lichessAnalysis `json:"analysis"`
+}
+
+type lichessPlayer struct {
+	User *struct {
+		Name  string `json:"name"`
+		Title string `json:"title"`
+	} `json:"user"`
+	AILevel    int  `json:"aiLevel"`
+	Rating     int  `json:"rating"`
+	RatingDiff *int `json:"ratingDiff"`
+}
+
+type lichessAnalysis struct {
+	Eval      *int   `json:"eval"`
+	Mate      *int   `json:"mate"`
+	Variation string `json:"variation"`
+	Judgment  *struct {
+		Name string `json:"name"`
+	} `json:"judgment"`
+}
+
+// ReadLichessGames reads games exported by the lichess API as NDJSON, one
+// JSON game per line. Blank lines are skipped.
+func ReadLichessGames(r io.Reader) ([]*Game, error) {
+	games := []*Game{}
+
+	lines := bufio.NewScanner(r)
+	lines.Buffer(make([]byte, 64*1024), 16*1024*1024)
+
+	for n := 1; lines.Scan(); n++ {
+		line := strings.TrimSpace(lines.Text())
+		if line == "" {
+			continue
+		}
+
+		g, err := ParseLichessGame([]byte(line))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", n, err)
+		}
+
+		games = append(games, g)
+	}
+
+	if err := lines.Err(); err != nil {
+		return nil, err
+	}
+
+	return games, nil
+}
+
+// ParseLichessGame converts a single game of the lichess JSON export into a
+// Game. Tags are filled as in lichess's own PGN export. Clocks become %clk
+// annotations, and computer analysis becomes %eval annotations, NAGs for
+// inaccuracies, mistakes and blunders, and variations with the best line.
+// Moves may be given in SAN or UCI notation.
+func ParseLichessGame(data []byte) (*Game, error) {
+	var lg lichessGame
+	if err := json.Unmarshal(data, &lg); err != nil {
+		return nil, err
+	}
+
+	g := newGame()
+
+	mode := "Casual"
+	if lg.Rated {
+		mode = "Rated"
+	}
+	g.SetTag("Event", fmt.Sprintf("%s %s game", mode, capitalize(lg.Speed)))
+	g.SetTag("Site", "https://lichess.org/"+lg.ID)
+
+	created := time.UnixMilli(lg.CreatedAt).UTC()
+	g.SetTag("Date", created.Format("2006.01.02"))
+	g.SetTag("Round", "-")
+	g.SetTag("White", lg.Players.White.name())
+	g.SetTag("Black", lg.Players.Black.name())
+	g.SetTag("Result", lg.result())
+	g.SetTag("UTCDate", created.Format("2006.01.02"))
+	g.SetTag("UTCTime", created.Format("15:04:05"))
+	lg.Players.White.setTags(g, "White")
+	lg.Players.Black.setTags(g, "Black")
+
+	variant := Standard
+	if lg.Variant != "" && lg.Variant != "standard" {
+		v, ok := LookupVariant(lg.Variant)
+		if !ok {
+			return nil, fmt.Errorf("unsupported variant %q", lg.Variant)
+		}
+		variant = v
+
+		name := v.Name()
+		if lg.Variant == "fromPosition" {
+			name = "From Position"
+		}
+		g.SetTag("Variant", name)
+	}
+
+	switch {
+	case lg.Clock != nil:
+		g.SetTag("TimeControl", fmt.Sprintf("%d+%d", lg.Clock.Initial, lg.Clock.Increment))
+	case lg.DaysPerTurn > 0 || lg.Speed == "correspondence":
+		g.SetTag("TimeControl", "-")
+	}
+
+	if lg.Opening != nil {
+		g.SetTag("ECO", lg.Opening.ECO)
+		g.SetTag("Opening", lg.Opening.Name)
+	}
+
+	g.SetTag("Termination", lichessTermination(lg.Status))
+
+	if lg.InitialFen != "" {
+		g.SetTag("SetUp", "1")
+		g.SetTag("FEN", lg.InitialFen)
+	}
+	g.SetResult(lg.result())
+
+	start, err := g.StartingPosition()
+	if err != nil {
+		return nil, err
+	}
+	if start.Variant() != variant {
+		return nil, fmt.Errorf("starting position does not match variant %q", lg.Variant)
+	}
+
+	sans, err := movesToSAN(start, strings.Fields(lg.Moves))
+	if err != nil {
+		return nil, err
+	}
+
+	line, err := NewVariation(start, sans)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range line.Moves {
+		g.SetMove(m.MoveNumber, m)
+	}
+
+	if err := lg.annotate(g, len(sans)); err != nil {
+		return nil, err
+	}
+
+	return g, nil
+}
+
+func (p lichessPlayer) name() string {
+	switch {
+	case p.User != nil:
+		return p.User.Name
+	case p.AILevel > 0:
+		return fmt.Sprintf("lichess AI level %d", p.AILevel)
+	}
+
+	return "Anonymous"
+}
+
+func (p lichessPlayer) setTags(g *Game, color string) {
+	if p.User != nil && p.User.Title != "" {
+		g.SetTag(color+"Title", p.User.Title)
+	}
+
+	if p.Rating > 0 {
+		g.SetTag(color+"Elo", strconv.Itoa(p.Rating))
+	}
+
+	if p.RatingDiff != nil {
+		g.SetTag(color+"RatingDiff", fmt.Sprintf("%+d", *p.RatingDiff))
+	}
+}
+
+func (lg lichessGame) result() string {
+	switch lg.Winner {
+	case "white":
+		return "1-0"
+	case "black":
+		return "0-1"
+	}
+
+	switch lg.Status {
+	case "created", "started", "aborted", "noStart", "unknownFinish":
+		return "*"
+	}
+
+	return "1/2-1/2"
+}
+
+func lichessTermination(status string) string {
+	switch status {
+	case "outoftime":
+		return "Time forfeit"
+	case "timeout":
+		return "Abandoned"
+	case "cheat":
+		return "Rules infraction"
+	case "created", "started":
+		return "Unterminated"
+	}
+
+	return "Normal"
+}
+
+// annotate adds the clocks and analysis of the export to the game's plies.
+func (lg lichessGame) annotate(g *Game, plies int) error {
+	for i, cs := range lg.Clocks {
+		if i >= plies {
+			break
+		}
+
+		clock := time.Duration(cs) * 10 * time.Millisecond
+		if err := g.SetCommand(i+1, "clk", FormatClock(clock)); err != nil {
+			return err
+		}
+	}
+
+	for i, a := range lg.Analysis {
+		ply := i + 1
+		if ply > plies {
+			break
+		}
+
+		switch {
+		case a.Mate != nil && *a.Mate != 0:
+			if err := g.SetEvaluation(ply, Evaluation{Mate: *a.Mate}); err != nil {
+				return err
+			}
+		case a.Eval != nil:
+			if err := g.SetEvaluation(ply, Evaluation{Centipawns: *a.Eval}); err != nil {
+				return err
+			}
+		}
+
+		if a.Judgment == nil {
+			continue
+		}
+
+		judgement := map[string]Judgement{
+			"Inaccuracy": Inaccuracy,
+			"Mistake":    Mistake,
+			"Blunder":    Blunder,
+		}[a.Judgment.Name]
+		if nag := judgement.NAG(); nag != "" {
+			if err := g.AddAnnotation(ply, nag); err != nil {
+				return err
+			}
+		}
+
+		if a.Variation != "" {
+			if _, err := g.AddVariation(ply, strings.Fields(a.Variation)); err != nil {
+				return fmt.Errorf("ply %d: %v", ply, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+var uciMovePattern = regexp.MustCompile(`^([a-h][1-8][a-h][1-8][qrbnk]?|[PNBRQK]@[a-h][1-8])$`)
+
+// movesToSAN converts moves played from start to SAN. Moves already in SAN
+// are kept. A list is read as UCI when its first move is written in UCI.
+func movesToSAN(start *Position, moves []string) ([]string, error) {
+	if len(moves) == 0 || !uciMovePattern.MatchString(moves[0]) || strings.Contains(moves[0], "@") {
+		return moves, nil
+	}
+
+	sans := make([]string, 0, len(moves))
+	pos := start
+	for i, m := range moves {
+		san, err := pos.UCIToSAN(m)
+		if err != nil {
+			return nil, fmt.Errorf("ply %d: %v", i+1, err)
+		}
+
+		if pos, err = pos.PlayUCI(m); err != nil {
+			return nil, fmt.Errorf("ply %d: %v", i+1, err)
+		}
+
+		sans = append(sans, san)
+	}
+
+	return sans, nil
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+
+	return strings.ToUpper(s[:1]) + s[1:]
+}
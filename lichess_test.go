@@ -0,0 +1,96 @@
+package pgn
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadLichessGames(t *testing.T) {
+	f, err := os.Open("testdata/lichess_games.ndjson")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	games, err := ReadLichessGames(f)
+	if err != nil {
+		t.Fatalf("ReadLichessGames() error: %v", err)
+	}
+
+	if len(games) != 3 {
+		t.Fatalf("len(games) = %d, want 3", len(games))
+	}
+
+	blitz := games[0]
+	tags := map[string]string{
+		"Event":           "Rated Blitz game",
+		"Site":            "https://lichess.org/q7ZvsdUF",
+		"Date":            "2017.12.28",
+		"White":           "Lance5500",
+		"Black":           "TryingHard87",
+		"Result":          "1-0",
+		"UTCTime":         "23:52:30",
+		"WhiteElo":        "2389",
+		"BlackRatingDiff": "-4",
+		"WhiteTitle":      "FM",
+		"TimeControl":     "180+0",
+		"ECO":             "C20",
+		"Termination":     "Normal",
+	}
+	for name, want := range tags {
+		if got := blitz.GetTag(name); got != want {
+			t.Errorf("tag %s = %q, want %q", name, got, want)
+		}
+	}
+
+	if blitz.Result() != "1-0" || blitz.TimeClass() != Blitz {
+		t.Errorf("Result(), TimeClass() = %q, %v, want 1-0, blitz", blitz.Result(), blitz.TimeClass())
+	}
+
+	plies := blitz.Plies()
+	if len(plies) != 7 {
+		t.Fatalf("len(Plies()) = %d, want 7", len(plies))
+	}
+	if clock, ok := plies[2].Clock(); !ok || clock != 178*time.Second {
+		t.Errorf("ply 3 clock = %v, %v, want 2m58s", clock, ok)
+	}
+	if eval, ok := plies[4].Evaluation(); !ok || eval.Centipawns != 40 {
+		t.Errorf("ply 5 evaluation = %+v, %v, want 0.4", eval, ok)
+	}
+
+	blunder := plies[5]
+	if len(blunder.Annotations) != 1 || blunder.Annotations[0] != "4" || len(blunder.Variations) != 1 {
+		t.Errorf("Nf6 annotations = %v with %d variations, want $4 and the best line", blunder.Annotations, len(blunder.Variations))
+	}
+
+	uci := games[1]
+	if got := strings.Join(uci.mainline(), " "); got != "e4 c5 Nf3 d6 d4 cxd4 Nxd4 Nf6 Nc3 a6" {
+		t.Errorf("moves from UCI = %s", got)
+	}
+	if uci.White() != "lichess AI level 3" || uci.Result() != "0-1" || uci.GetTag("Event") != "Casual Rapid game" {
+		t.Errorf("White, Result, Event = %q, %q, %q", uci.White(), uci.Result(), uci.GetTag("Event"))
+	}
+
+	corr := games[2]
+	if corr.GetTag("TimeControl") != "-" || corr.Result() != "1/2-1/2" || corr.GetTag("Variant") != "From Position" {
+		t.Errorf("TimeControl, Result, Variant = %q, %q, %q", corr.GetTag("TimeControl"), corr.Result(), corr.GetTag("Variant"))
+	}
+	if pos, err := corr.PositionAt(2); err != nil || pos.FEN() != "8/3k4/8/8/8/8/3KP3/8 w - - 2 2" {
+		t.Errorf("final position = %v, %v", pos, err)
+	}
+}
+
+func TestParseLichessGameErrors(t *testing.T) {
+	for _, input := range []string{
+		`{"id":"x","moves":"e4 e4"}`,
+		`{"id":"x","variant":"racingKings","moves":""}`,
+		`{"id":"x","moves":"e2e4 e2e4"}`,
+		`not json`,
+	} {
+		if _, err := ParseLichessGame([]byte(input)); err == nil {
+			t.Errorf("ParseLichessGame(%s) expected error", input)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package pgn
+
+import (
+	"fmt"
+	"strings"
+)
+
+// figurineLetters maps SAN piece letters to figurines. Figurine notation
+// uses the white figurines for both sides.
+var figurineLetters = strings.NewReplacer("K", "♔", "Q", "♕", "R", "♖", "B", "♗", "N", "♘")
+
+// FigurineSAN writes a SAN move in figurine notation, as in "♘xe5".
+func FigurineSAN(san string) string {
+	return figurineLetters.Replace(san)
+}
+
+// Markdown returns the game as Markdown: a heading with the players, the
+// event details, a table of all tags and the moves in figurine notation
+// with comments in italics and variations in parentheses.
+func (g *Game) Markdown() string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "## %s – %s\n\n", escapeMarkdown(g.White()), escapeMarkdown(g.Black()))
+	if details := gameDetails(g); len(details) > 0 {
+		sb.WriteString(escapeMarkdown(strings.Join(details, ", ")) + "\n\n")
+	}
+
+	if names := g.exportTagOrder(); len(names) > 0 {
+		sb.WriteString("| Tag | Value |\n| --- | --- |\n")
+		for _, name := range names {
+			fmt.Fprintf(&sb, "| %s | %s |\n", name, strings.ReplaceAll(escapeMarkdown(g.tags[name]), "|", `\|`))
+		}
+		sb.WriteString("\n")
+	}
+
+	tokens := append(markdownTokens(g.comments, g.Plies()), "**"+g.exportResult()+"**")
+	// A paragraph starting with "1." would be read as a list.
+	if first := tokens[0]; first[0] >= '0' && first[0] <= '9' {
+		tokens[0] = strings.Replace(first, ".", `\.`, 1)
+	}
+	sb.WriteString(strings.Join(tokens, " ") + "\n")
+
+	return sb.String()
+}
+
+func markdownTokens(comments []string, plies []Ply) []string {
+	tokens := []string{}
+	for _, c := range comments {
+		if text := StripCommands(c); text != "" {
+			tokens = append(tokens, "_"+escapeMarkdown(text)+"_")
+		}
+	}
+
+	needNumber := true
+	for _, p := range plies {
+		san := FigurineSAN(p.SAN)
+		for _, nag := range p.Annotations {
+			if nag != diagramNAG {
+				san += nagGlyph(nag)
+			}
+		}
+
+		switch {
+		case p.Color == White:
+			tokens = append(tokens, fmt.Sprintf("%d. %s", p.MoveNumber, san))
+		case needNumber:
+			tokens = append(tokens, fmt.Sprintf("%d... %s", p.MoveNumber, san))
+		default:
+			tokens = append(tokens, san)
+		}
+
+		for _, c := range p.Comments {
+			if text := StripCommands(c); text != "" {
+				tokens = append(tokens, "_"+escapeMarkdown(text)+"_")
+			}
+		}
+
+		for _, v := range p.Variations {
+			variation := markdownTokens(v.Comments, v.Plies())
+			if len(variation) == 0 {
+				continue
+			}
+
+			variation[0] = "(" + variation[0]
+			variation[len(variation)-1] += ")"
+			tokens = append(tokens, variation...)
+		}
+
+		needNumber = len(p.Comments) > 0 || len(p.Variations) > 0
+	}
+
+	return tokens
+}
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	"`", "\\`",
+	`*`, `\*`,
+	`_`, `\_`,
+	`[`, `\[`,
+	`]`, `\]`,
+	`<`, `\<`,
+	`>`, `\>`,
+	`#`, `\#`,
+)
+
+func escapeMarkdown(s string) string {
+	return markdownEscaper.Replace(s)
+}
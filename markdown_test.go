@@ -0,0 +1,49 @@
+package pgn
+
+import "testing"
+
+func TestGameMarkdown(t *testing.T) {
+	game, err := New(`[Event "Club Championship"]
+[Site "?"]
+[White "Dupont"]
+[Black "Martin_2"]
+[Result "1/2-1/2"]
+[Opening "Ruy Lopez | Closed"]
+
+1. e4 e5 2. Nf3 Nc6 3. Bb5 {Spanish *classic* [%clk 0:05:00]} a6 (3... Nf6 4. O-O Nxe4) 4. Ba4 $14 Nf6! 1/2-1/2`)
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+
+	expected := "## Dupont – Martin\\_2\n\n" +
+		"Club Championship\n\n" +
+		"| Tag | Value |\n| --- | --- |\n" +
+		"| Event | Club Championship |\n" +
+		"| Site | ? |\n" +
+		"| White | Dupont |\n" +
+		"| Black | Martin\\_2 |\n" +
+		"| Result | 1/2-1/2 |\n" +
+		"| Opening | Ruy Lopez \\| Closed |\n\n" +
+		"1\\. e4 e5 2. ♘f3 ♘c6 3. ♗b5 _Spanish \\*classic\\*_ 3... a6 (3... ♘f6 4. O-O ♘xe4) 4. ♗a4⩲ ♘f6! **1/2-1/2**\n"
+
+	if got := game.Markdown(); got != expected {
+		t.Errorf("Markdown() =\n%s\nwant\n%s", got, expected)
+	}
+}
+
+func TestFigurineSAN(t *testing.T) {
+	tests := map[string]string{
+		"e4":     "e4",
+		"Nxe5+":  "♘xe5+",
+		"O-O-O":  "O-O-O",
+		"exd8=Q": "exd8=♕",
+		"Rad1#":  "♖ad1#",
+		"Kxb2":   "♔xb2",
+	}
+
+	for san, expected := range tests {
+		if got := FigurineSAN(san); got != expected {
+			t.Errorf("FigurineSAN(%q) = %q, want %q", san, got, expected)
+		}
+	}
+}
@@ -0,0 +1,214 @@
+package pgn
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MarkColor is the color of an arrow or highlighted square, using the
+// letters of ChessBase and lichess studies.
+type MarkColor byte
+
+const (
+	MarkGreen  MarkColor = 'G'
+	MarkRed    MarkColor = 'R'
+	MarkYellow MarkColor = 'Y'
+	MarkBlue   MarkColor = 'B'
+)
+
+func parseMarkColor(c byte) (MarkColor, error) {
+	switch MarkColor(c) {
+	case MarkGreen, MarkRed, MarkYellow, MarkBlue:
+		return MarkColor(c), nil
+	}
+
+	return 0, fmt.Errorf("invalid mark color %q", c)
+}
+
+// Arrow is an arrow drawn on the board by a [%cal ...] annotation.
+type Arrow struct {
+	Color MarkColor
+	From  Square
+	To    Square
+}
+
+func (a Arrow) String() string {
+	return string(a.Color) + a.From.String() + a.To.String()
+}
+
+// Highlight is a square highlighted by a [%csl ...] annotation.
+type Highlight struct {
+	Color  MarkColor
+	Square Square
+}
+
+func (h Highlight) String() string {
+	return string(h.Color) + h.Square.String()
+}
+
+// ParseArrows parses the value of a %cal command, such as "Ge2e4,Rd8d1".
+func ParseArrows(value string) ([]Arrow, error) {
+	arrows := []Arrow{}
+
+	for _, field := range splitMarks(value) {
+		if len(field) != 5 {
+			return nil, fmt.Errorf("invalid arrow %q", field)
+		}
+
+		color, err := parseMarkColor(field[0])
+		if err != nil {
+			return nil, err
+		}
+
+		from, err := ParseSquare(field[1:3])
+		if err != nil {
+			return nil, err
+		}
+
+		to, err := ParseSquare(field[3:5])
+		if err != nil {
+			return nil, err
+		}
+
+		arrows = append(arrows, Arrow{Color: color, From: from, To: to})
+	}
+
+	return arrows, nil
+}
+
+// ParseHighlights parses the value of a %csl command, such as "Gf7,Rd4".
+func ParseHighlights(value string) ([]Highlight, error) {
+	highlights := []Highlight{}
+
+	for _, field := range splitMarks(value) {
+		if len(field) != 3 {
+			return nil, fmt.Errorf("invalid highlight %q", field)
+		}
+
+		color, err := parseMarkColor(field[0])
+		if err != nil {
+			return nil, err
+		}
+
+		sq, err := ParseSquare(field[1:3])
+		if err != nil {
+			return nil, err
+		}
+
+		highlights = append(highlights, Highlight{Color: color, Square: sq})
+	}
+
+	return highlights, nil
+}
+
+func splitMarks(value string) []string {
+	return strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || r == ' '
+	})
+}
+
+// FormatArrows writes arrows as the value of a %cal command.
+func FormatArrows(arrows []Arrow) string {
+	fields := make([]string, len(arrows))
+	for i, a := range arrows {
+		fields[i] = a.String()
+	}
+
+	return strings.Join(fields, ",")
+}
+
+// FormatHighlights writes highlights as the value of a %csl command.
+func FormatHighlights(highlights []Highlight) string {
+	fields := make([]string, len(highlights))
+	for i, h := range highlights {
+		fields[i] = h.String()
+	}
+
+	return strings.Join(fields, ",")
+}
+
+// Arrows returns the arrows drawn after the ply by its %cal annotations.
+// Malformed arrows are skipped.
+func (p Ply) Arrows() []Arrow {
+	return commandArrows(p.Commands())
+}
+
+// Highlights returns the squares highlighted after the ply by its %csl
+// annotations. Malformed highlights are skipped.
+func (p Ply) Highlights() []Highlight {
+	return commandHighlights(p.Commands())
+}
+
+// Marks returns the arrows and highlights shown in the position after the
+// given ply. Ply 0 is the starting position, marked by the comments before
+// the first move.
+func (g *Game) Marks(ply int) ([]Arrow, []Highlight) {
+	var commands []Command
+
+	if ply == 0 {
+		for _, c := range g.comments {
+			commands = append(commands, ParseCommands(c)...)
+		}
+	} else if plies := g.Plies(); ply > 0 && ply <= len(plies) {
+		commands = plies[ply-1].Commands()
+	}
+
+	return commandArrows(commands), commandHighlights(commands)
+}
+
+// SetMarks replaces the %cal and %csl annotations of the given ply, counted
+// from 1. Empty lists remove the annotation.
+func (g *Game) SetMarks(ply int, arrows []Arrow, highlights []Highlight) error {
+	comments, err := g.plyComments(ply)
+	if err != nil {
+		return err
+	}
+
+	*comments = removeCommand(*comments, "cal")
+	*comments = removeCommand(*comments, "csl")
+
+	if len(highlights) > 0 {
+		*comments = setCommand(*comments, "csl", FormatHighlights(highlights))
+	}
+	if len(arrows) > 0 {
+		*comments = setCommand(*comments, "cal", FormatArrows(arrows))
+	}
+
+	return nil
+}
+
+func commandArrows(commands []Command) []Arrow {
+	arrows := []Arrow{}
+
+	for _, c := range commands {
+		if c.Name != "cal" {
+			continue
+		}
+
+		for _, field := range splitMarks(c.Value) {
+			if a, err := ParseArrows(field); err == nil {
+				arrows = append(arrows, a...)
+			}
+		}
+	}
+
+	return arrows
+}
+
+func commandHighlights(commands []Command) []Highlight {
+	highlights := []Highlight{}
+
+	for _, c := range commands {
+		if c.Name != "csl" {
+			continue
+		}
+
+		for _, field := range splitMarks(c.Value) {
+			if h, err := ParseHighlights(field); err == nil {
+				highlights = append(highlights, h...)
+			}
+		}
+	}
+
+	return highlights
+}
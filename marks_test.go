@@ -0,0 +1,86 @@
+package pgn
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseArrows(t *testing.T) {
+	arrows, err := ParseArrows("Ge2e4,Rd8d1")
+	if err != nil {
+		t.Fatalf("ParseArrows() error: %v", err)
+	}
+
+	expected := []Arrow{
+		{Color: MarkGreen, From: newSquare(4, 1), To: newSquare(4, 3)},
+		{Color: MarkRed, From: newSquare(3, 7), To: newSquare(3, 0)},
+	}
+	if !reflect.DeepEqual(arrows, expected) {
+		t.Errorf("ParseArrows() = %v, want %v", arrows, expected)
+	}
+
+	if got := FormatArrows(arrows); got != "Ge2e4,Rd8d1" {
+		t.Errorf("FormatArrows() = %q, want %q", got, "Ge2e4,Rd8d1")
+	}
+
+	for _, input := range []string{"Xe2e4", "Ge2e9", "Ge2"} {
+		if _, err := ParseArrows(input); err == nil {
+			t.Errorf("ParseArrows(%q) expected error", input)
+		}
+	}
+
+	highlights, err := ParseHighlights("Gf7, Yd4")
+	if err != nil {
+		t.Fatalf("ParseHighlights() error: %v", err)
+	}
+	if got := FormatHighlights(highlights); got != "Gf7,Yd4" {
+		t.Errorf("FormatHighlights() = %q, want %q", got, "Gf7,Yd4")
+	}
+}
+
+func TestGameMarks(t *testing.T) {
+	input := `[Result "*"]
+
+{[%csl Ge4]} 1. e4 {[%cal Gg1f3,Rd8h4] [%csl Rf7] Aim at f7} e5 2. Nf3 *`
+
+	game, err := New(input)
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+
+	if _, highlights := game.Marks(0); FormatHighlights(highlights) != "Ge4" {
+		t.Errorf("Marks(0) highlights = %v, want [Ge4]", highlights)
+	}
+
+	ply := game.Plies()[0]
+	if got := FormatArrows(ply.Arrows()); got != "Gg1f3,Rd8h4" {
+		t.Errorf("Arrows() = %q, want %q", got, "Gg1f3,Rd8h4")
+	}
+	if got := FormatHighlights(ply.Highlights()); got != "Rf7" {
+		t.Errorf("Highlights() = %q, want %q", got, "Rf7")
+	}
+
+	again, err := New(game.PGN())
+	if err != nil {
+		t.Fatalf("New() on exported PGN error: %v", err)
+	}
+	if arrows, _ := again.Marks(1); FormatArrows(arrows) != "Gg1f3,Rd8h4" {
+		t.Errorf("arrows after export = %v, want Gg1f3,Rd8h4", arrows)
+	}
+
+	arrows := []Arrow{{Color: MarkBlue, From: newSquare(6, 7), To: newSquare(5, 5)}}
+	if err := game.SetMarks(2, arrows, nil); err != nil {
+		t.Fatalf("SetMarks() error: %v", err)
+	}
+	if err := game.SetMarks(1, nil, nil); err != nil {
+		t.Fatalf("SetMarks() error: %v", err)
+	}
+
+	plies := game.Plies()
+	if !reflect.DeepEqual(plies[0].Comments, []string{"Aim at f7"}) {
+		t.Errorf("comments after clearing marks = %v, want [Aim at f7]", plies[0].Comments)
+	}
+	if !reflect.DeepEqual(plies[1].Comments, []string{"[%cal Bg8f6]"}) {
+		t.Errorf("comments after SetMarks() = %v, want [[%%cal Bg8f6]]", plies[1].Comments)
+	}
+}
@@ -0,0 +1,400 @@
+package pgn
+
+// move is a single move on the board. Castling is encoded as the king
+// moving onto its own rook, the convention used by Polyglot books and by
+// Chess960 UCI engines.
+type move struct {
+	from      Square
+	to        Square
+	promotion PieceType
+	castling  bool
+	drop      PieceType
+}
+
+var (
+	knightOffsets = [8][2]int{{1, 2}, {2, 1}, {2, -1}, {1, -2}, {-1, -2}, {-2, -1}, {-2, 1}, {-1, 2}}
+	kingOffsets   = [8][2]int{{1, 0}, {1, 1}, {0, 1}, {-1, 1}, {-1, 0}, {-1, -1}, {0, -1}, {1, -1}}
+	bishopDirs    = [4][2]int{{1, 1}, {1, -1}, {-1, 1}, {-1, -1}}
+	rookDirs      = [4][2]int{{1, 0}, {-1, 0}, {0, 1}, {0, -1}}
+)
+
+func offsetSquare(sq Square, df, dr int) (Square, bool) {
+	file := sq.File() + df
+	rank := sq.Rank() + dr
+
+	if file < 0 || file > 7 || rank < 0 || rank > 7 {
+		return NoSquare, false
+	}
+
+	return newSquare(file, rank), true
+}
+
+func pawnDirection(c Color) int {
+	if c == White {
+		return 1
+	}
+
+	return -1
+}
+
+func backRank(c Color) int {
+	if c == White {
+		return 0
+	}
+
+	return 7
+}
+
+// isAttacked reports whether any piece of color by attacks sq.
+func (pos *Position) isAttacked(sq Square, by Color) bool {
+	for _, off := range knightOffsets {
+		if t, ok := offsetSquare(sq, off[0], off[1]); ok && pos.board[t] == (Piece{Type: Knight, Color: by}) {
+			return true
+		}
+	}
+
+	for _, off := range kingOffsets {
+		if t, ok := offsetSquare(sq, off[0], off[1]); ok && pos.board[t] == (Piece{Type: King, Color: by}) {
+			return true
+		}
+	}
+
+	dir := pawnDirection(by)
+	for _, df := range []int{-1, 1} {
+		if t, ok := offsetSquare(sq, df, -dir); ok && pos.board[t] == (Piece{Type: Pawn, Color: by}) {
+			return true
+		}
+	}
+
+	if pos.slidingAttack(sq, by, bishopDirs[:], Bishop) || pos.slidingAttack(sq, by, rookDirs[:], Rook) {
+		return true
+	}
+
+	return false
+}
+
+func (pos *Position) slidingAttack(sq Square, by Color, dirs [][2]int, slider PieceType) bool {
+	for _, d := range dirs {
+		t := sq
+		for {
+			var ok bool
+			t, ok = offsetSquare(t, d[0], d[1])
+			if !ok {
+				break
+			}
+
+			p := pos.board[t]
+			if p.IsEmpty() {
+				continue
+			}
+
+			if p.Color == by && (p.Type == slider || p.Type == Queen) {
+				return true
+			}
+			break
+		}
+	}
+
+	return false
+}
+
+func (pos *Position) InCheck() bool {
+	return pos.rules().inCheck(pos)
+}
+
+func (pos *Position) kingAttacked() bool {
+	king := pos.kingSquare(pos.turn)
+	return king != NoSquare && pos.isAttacked(king, pos.turn.Other())
+}
+
+// pseudoLegalMoves generates every move for the side to move that obeys the
+// piece movement rules, without checking whether the king is left in check.
+func (pos *Position) pseudoLegalMoves() []move {
+	moves := make([]move, 0, 48)
+	us := pos.turn
+
+	for from := Square(0); from < 64; from++ {
+		p := pos.board[from]
+		if p.IsEmpty() || p.Color != us {
+			continue
+		}
+
+		switch p.Type {
+		case Pawn:
+			moves = pos.pawnMoves(moves, from)
+		case Knight:
+			moves = pos.stepMoves(moves, from, knightOffsets[:])
+		case Bishop:
+			moves = pos.slideMoves(moves, from, bishopDirs[:])
+		case Rook:
+			moves = pos.slideMoves(moves, from, rookDirs[:])
+		case Queen:
+			moves = pos.slideMoves(moves, from, bishopDirs[:])
+			moves = pos.slideMoves(moves, from, rookDirs[:])
+		case King:
+			moves = pos.stepMoves(moves, from, kingOffsets[:])
+		}
+	}
+
+	return pos.castlingMoves(moves)
+}
+
+func (pos *Position) stepMoves(moves []move, from Square, offsets [][2]int) []move {
+	for _, off := range offsets {
+		to, ok := offsetSquare(from, off[0], off[1])
+		if !ok {
+			continue
+		}
+
+		if target := pos.board[to]; target.IsEmpty() || target.Color != pos.turn {
+			moves = append(moves, move{from: from, to: to})
+		}
+	}
+
+	return moves
+}
+
+func (pos *Position) slideMoves(moves []move, from Square, dirs [][2]int) []move {
+	for _, d := range dirs {
+		to := from
+		for {
+			var ok bool
+			to, ok = offsetSquare(to, d[0], d[1])
+			if !ok {
+				break
+			}
+
+			target := pos.board[to]
+			if target.IsEmpty() {
+				moves = append(moves, move{from: from, to: to})
+				continue
+			}
+
+			if target.Color != pos.turn {
+				moves = append(moves, move{from: from, to: to})
+			}
+			break
+		}
+	}
+
+	return moves
+}
+
+func (pos *Position) pawnMoves(moves []move, from Square) []move {
+	dir := pawnDirection(pos.turn)
+	lastRank := backRank(pos.turn.Other())
+
+	add := func(to Square) {
+		if to.Rank() == lastRank {
+			for _, pt := range []PieceType{Queen, Rook, Bishop, Knight} {
+				moves = append(moves, move{from: from, to: to, promotion: pt})
+			}
+			return
+		}
+		moves = append(moves, move{from: from, to: to})
+	}
+
+	if to, ok := offsetSquare(from, 0, dir); ok && pos.board[to].IsEmpty() {
+		add(to)
+
+		startRank := backRank(pos.turn) + dir
+		if from.Rank() == startRank {
+			if to2, ok := offsetSquare(to, 0, dir); ok && pos.board[to2].IsEmpty() {
+				add(to2)
+			}
+		}
+	}
+
+	for _, df := range []int{-1, 1} {
+		to, ok := offsetSquare(from, df, dir)
+		if !ok {
+			continue
+		}
+
+		target := pos.board[to]
+		if (!target.IsEmpty() && target.Color != pos.turn) || (to == pos.ep && target.IsEmpty()) {
+			add(to)
+		}
+	}
+
+	return moves
+}
+
+func (pos *Position) castlingMoves(moves []move) []move {
+	us := pos.turn
+	king := pos.kingSquare(us)
+	if king == NoSquare || king.Rank() != backRank(us) {
+		return moves
+	}
+
+	for side := kingSide; side <= queenSide; side++ {
+		rook := pos.castling[us][side]
+		if rook == NoSquare || pos.board[rook] != (Piece{Type: Rook, Color: us}) {
+			continue
+		}
+
+		if pos.castlingPathClear(king, rook, side) {
+			moves = append(moves, move{from: king, to: rook, castling: true})
+		}
+	}
+
+	return moves
+}
+
+// castlingDestinations returns where the king and rook end up after castling
+// to the given side. The destinations are the same in standard chess and in
+// Chess960.
+func castlingDestinations(c Color, side int) (Square, Square) {
+	rank := backRank(c)
+	if side == kingSide {
+		return newSquare(6, rank), newSquare(5, rank)
+	}
+
+	return newSquare(2, rank), newSquare(3, rank)
+}
+
+func (pos *Position) castlingPathClear(king, rook Square, side int) bool {
+	us := pos.turn
+	kingTo, rookTo := castlingDestinations(us, side)
+
+	lo, hi := minSquare(king, rook, kingTo, rookTo), maxSquare(king, rook, kingTo, rookTo)
+	for sq := lo; sq <= hi; sq++ {
+		if sq != king && sq != rook && !pos.board[sq].IsEmpty() {
+			return false
+		}
+	}
+
+	lo, hi = minSquare(king, kingTo), maxSquare(king, kingTo)
+	for sq := lo; sq <= hi; sq++ {
+		if pos.isAttacked(sq, us.Other()) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func minSquare(squares ...Square) Square {
+	m := squares[0]
+	for _, sq := range squares[1:] {
+		if sq < m {
+			m = sq
+		}
+	}
+	return m
+}
+
+func maxSquare(squares ...Square) Square {
+	m := squares[0]
+	for _, sq := range squares[1:] {
+		if sq > m {
+			m = sq
+		}
+	}
+	return m
+}
+
+func (pos *Position) legalMoves() []move {
+	rules := pos.rules()
+	if rules.variantEnd(pos) != "" {
+		return nil
+	}
+
+	return rules.legalMoves(pos)
+}
+
+// kingSafeMoves keeps the moves that do not leave the mover's king attacked.
+// A side without a king, as in horde, may make any move.
+func (pos *Position) kingSafeMoves(pseudo []move) []move {
+	legal := pseudo[:0]
+
+	for _, m := range pseudo {
+		next := pos.play(m)
+		king := next.kingSquare(pos.turn)
+		if king == NoSquare || !next.isAttacked(king, pos.turn.Other()) {
+			legal = append(legal, m)
+		}
+	}
+
+	return legal
+}
+
+func (pos *Position) play(m move) *Position {
+	return pos.rules().play(pos, m)
+}
+
+// playStandard returns the position after m, which is assumed to be legal,
+// under the rules of standard chess.
+func (pos *Position) playStandard(m move) *Position {
+	next := pos.copy()
+	us := pos.turn
+	piece := pos.board[m.from]
+	captured := pos.board[m.to]
+
+	next.ep = NoSquare
+	next.halfmove++
+
+	if m.castling {
+		side := queenSide
+		if m.to.File() > m.from.File() {
+			side = kingSide
+		}
+
+		kingTo, rookTo := castlingDestinations(us, side)
+		next.board[m.from] = NoPiece
+		next.board[m.to] = NoPiece
+		next.board[kingTo] = piece
+		next.board[rookTo] = pos.board[m.to]
+		next.castling[us] = [2]Square{NoSquare, NoSquare}
+	} else {
+		next.board[m.from] = NoPiece
+		next.board[m.to] = piece
+
+		if piece.Type == Pawn {
+			next.halfmove = 0
+
+			if m.to == pos.ep && captured.IsEmpty() && m.from.File() != m.to.File() {
+				next.board[newSquare(m.to.File(), m.from.Rank())] = NoPiece
+			}
+
+			if d := m.to.Rank() - m.from.Rank(); d == 2 || d == -2 {
+				next.ep = newSquare(m.from.File(), (m.from.Rank()+m.to.Rank())/2)
+			}
+
+			if m.promotion != NoPieceType {
+				next.board[m.to] = Piece{Type: m.promotion, Color: us}
+			}
+		}
+
+		if !captured.IsEmpty() {
+			next.halfmove = 0
+		}
+
+		if piece.Type == King {
+			next.castling[us] = [2]Square{NoSquare, NoSquare}
+		}
+	}
+
+	for c := White; c <= Black; c++ {
+		for side := kingSide; side <= queenSide; side++ {
+			if rook := next.castling[c][side]; rook == m.from || rook == m.to {
+				next.castling[c][side] = NoSquare
+			}
+		}
+	}
+
+	if us == Black {
+		next.fullmove++
+	}
+	next.turn = us.Other()
+
+	return next
+}
+
+func (pos *Position) IsCheckmate() bool {
+	return pos.InCheck() && len(pos.legalMoves()) == 0
+}
+
+func (pos *Position) IsStalemate() bool {
+	return !pos.InCheck() && len(pos.legalMoves()) == 0
+}
@@ -2,14 +2,13 @@ package pgn
 
 import (
 	"fmt"
-	"log"
 	"strconv"
 )
 
 type parser struct {
 	l *lexer
 
-	errors []string
+	errors []*SyntaxError
 
 	currToken token
 	peekToken token
@@ -18,7 +17,7 @@ type parser struct {
 func newParser(l *lexer) *parser {
 	p := &parser{
 		l:      l,
-		errors: []string{},
+		errors: []*SyntaxError{},
 	}
 
 	p.nextToken()
@@ -28,49 +27,105 @@ func newParser(l *lexer) *parser {
 }
 
 func (p *parser) ParsePGN() (*Game, error) {
-	game := &Game{
-		tags:  map[string]string{},
-		moves: map[int]*Move{},
+	game := p.parseGame(false)
+
+	if len(p.Errors()) > 0 {
+		return nil, fmt.Errorf("parsing errors: %v", p.Errors())
 	}
 
+	return game, nil
+}
+
+// ParseGames parses every game of a PGN database. Each game ends with its
+// game termination marker.
+func (p *parser) ParseGames() ([]*Game, error) {
+	games := []*Game{}
+
+	for !p.currTokenIs(EOF) {
+		game := p.parseGame(true)
+
+		if len(p.Errors()) > 0 {
+			return nil, fmt.Errorf("game %d: parsing errors: %v", len(games)+1, p.Errors())
+		}
+
+		if len(game.tags) > 0 || len(game.moves) > 0 || game.result != "" {
+			games = append(games, game)
+		}
+	}
+
+	return games, nil
+}
+
+func (p *parser) parseGame(stopAtTermination bool) *Game {
+	game := newGame()
+
+	mainline := &Variation{}
+
 	for p.currToken.Type != EOF {
+		start := p.currToken
 		stmt := p.parseStatement()
 		if stmt != nil {
 			switch v := stmt.(type) {
 			case *TagPair:
 				game.SetTag(v.Name(), v.Value())
 			case *Move:
-				game.SetMove(v.Number(), v)
+				m := mainline.addMove(v)
+				game.SetMove(m.Number(), m)
+			case *comment:
+				mainline.addComment(v.Text)
+				game.comments = mainline.Comments
+			case *Variation:
+				mainline.addVariation(v)
 			case *gameTermination:
 				if v.Value() != game.GetTag("Result") {
-					p.errors = append(p.errors, "Game termination marker does not match game result in tag pair")
+					p.errorAt(start, "Game termination marker does not match game result in tag pair")
 				}
 				game.SetResult(v.Value())
+
+				if stopAtTermination {
+					return game
+				}
 			}
 		}
 	}
 
-	if len(p.Errors()) > 0 {
-		return nil, fmt.Errorf("parsing errors: %v", p.Errors())
-	}
-
-	return game, nil
+	return game
 }
 
 func (p *parser) parseStatement() stmt {
 	switch p.currToken.Type {
 	case LBRACKET:
-		return p.parseTagPair()
+		if tp := p.parseTagPair(); tp != nil {
+			return tp
+		}
+		return nil
 	case INTEGER:
-		return p.parseMove()
+		if m := p.parseMove(); m != nil {
+			return m
+		}
+		return nil
 	case SYMBOL:
 		if isGameResult(p.currToken.TokenLiteral()) {
 			gt := &gameTermination{TerminationValue: p.currToken.TokenLiteral()}
 			p.nextToken()
 			return gt
 		}
+		p.nextToken()
 		return nil
+	case COMMENT:
+		c := &comment{Text: p.currToken.TokenLiteral()}
+		p.nextToken()
+		return c
+	case LPAREN:
+		v := p.parseVariation()
+		p.nextToken()
+		return v
+	case ASTERIX:
+		gt := &gameTermination{TerminationValue: p.currToken.TokenLiteral()}
+		p.nextToken()
+		return gt
 	default:
+		p.nextToken()
 		return nil
 	}
 }
@@ -81,6 +136,7 @@ func (p *parser) parseTagPair() *TagPair {
 	}
 
 	if !p.expectPeek(SYMBOL) {
+		p.nextToken()
 		return nil
 	}
 
@@ -105,7 +161,9 @@ func (p *parser) parseMove() *Move {
 
 	moveNumInt, err := strconv.Atoi(p.currToken.TokenLiteral())
 	if err != nil {
-		log.Fatalf("Couldn't convert string to integer for moves: %s", p.currToken.TokenLiteral())
+		p.errorAt(p.currToken, "invalid move number %s", p.currToken.TokenLiteral())
+		p.nextToken()
+		return nil
 	}
 
 	move := &Move{
@@ -115,8 +173,10 @@ func (p *parser) parseMove() *Move {
 	}
 
 	//Zero or more periods
+	periods := 0
 	for p.peekTokenIs(PERIOD) {
 		p.nextToken()
+		periods++
 	}
 
 	if !p.expectPeek(SYMBOL) {
@@ -127,33 +187,114 @@ func (p *parser) parseMove() *Move {
 		return move
 	}
 
-	move.MoveWhite = p.currToken.TokenLiteral()
+	firstAt := p.currToken.position()
+	firstMove, glyph := splitGlyph(p.currToken.TokenLiteral())
+	firstAnnotations, firstComments, firstVariations := p.parseMoveAnnotations(glyph)
+
+	// "12... Qe7" followed by the next move number is a lone black move, as
+	// in games that start from a position with black to move.
+	if periods >= 3 && (!p.currTokenIs(SYMBOL) || isGameResult(p.currToken.TokenLiteral())) {
+		move.MoveBlack = firstMove
+		move.blackAt = firstAt
+		move.BlackAnnotations = firstAnnotations
+		move.BlackComments = firstComments
+		move.BlackVariations = firstVariations
+		return move
+	}
+
+	move.MoveWhite = firstMove
+	move.whiteAt = firstAt
+	move.WhiteAnnotations = firstAnnotations
+	move.WhiteComments = firstComments
+	move.WhiteVariations = firstVariations
 
-	for p.peekTokenIs(NAG) {
+	// Exporters that comment white's move repeat the move number before
+	// black's reply: "1. e4 {[%clk 0:03:00]} 1... e5".
+	if p.currTokenIs(INTEGER) && p.currToken.TokenLiteral() == strconv.Itoa(moveNumInt) && p.peekTokenIs(PERIOD) {
+		for p.peekTokenIs(PERIOD) {
+			p.nextToken()
+		}
 		p.nextToken()
-		move.WhiteAnnotations = append(move.WhiteAnnotations, p.currToken.TokenLiteral())
 	}
 
-	p.nextToken()
-
-	if isGameResult(p.currToken.TokenLiteral()) {
+	if !p.currTokenIs(SYMBOL) || isGameResult(p.currToken.TokenLiteral()) {
 		return move
 	}
 
-	move.MoveBlack = p.currToken.TokenLiteral()
+	blackMove, glyph := splitGlyph(p.currToken.TokenLiteral())
+	move.MoveBlack = blackMove
+	move.blackAt = p.currToken.position()
+	move.BlackAnnotations, move.BlackComments, move.BlackVariations = p.parseMoveAnnotations(glyph)
 
-	for p.peekTokenIs(NAG) {
-		p.nextToken()
-		move.BlackAnnotations = append(move.BlackAnnotations, p.currToken.TokenLiteral())
+	return move
+}
+
+// parseMoveAnnotations collects the NAGs, comments and variations that
+// follow the current move and leaves the parser on the first token after
+// them. A suffix annotation already split off the move is passed in as glyph.
+func (p *parser) parseMoveAnnotations(glyph string) ([]string, []string, []*Variation) {
+	annotations := []string{}
+	if glyph != "" {
+		annotations = append(annotations, glyph)
 	}
 
+	var comments []string
+	var variations []*Variation
+
+	for {
+		switch {
+		case p.peekTokenIs(NAG):
+			p.nextToken()
+			annotations = append(annotations, p.currToken.TokenLiteral())
+		case p.peekTokenIs(COMMENT):
+			p.nextToken()
+			comments = append(comments, p.currToken.TokenLiteral())
+		case p.peekTokenIs(LPAREN):
+			p.nextToken()
+			variations = append(variations, p.parseVariation())
+		default:
+			p.nextToken()
+			return annotations, comments, variations
+		}
+	}
+}
+
+// parseVariation parses a recursive annotation variation from its opening
+// parenthesis and leaves the parser on the closing one.
+func (p *parser) parseVariation() *Variation {
+	variation := &Variation{}
+	open := p.currToken
+
 	p.nextToken()
+	for !p.currTokenIs(RPAREN) && !p.currTokenIs(EOF) {
+		switch v := p.parseStatement().(type) {
+		case *Move:
+			variation.addMove(v)
+		case *comment:
+			variation.addComment(v.Text)
+		case *Variation:
+			variation.addVariation(v)
+		}
+	}
 
-	return move
+	if p.currTokenIs(EOF) {
+		p.errorAt(open, "unterminated variation")
+	}
+
+	return variation
 }
 
 func (p *parser) Errors() []string {
-	return p.errors
+	messages := make([]string, len(p.errors))
+	for i, err := range p.errors {
+		messages[i] = err.Error()
+	}
+
+	return messages
+}
+
+func (p *parser) errorAt(t token, format string, args ...any) {
+	p.errors = append(p.errors, &SyntaxError{Line: t.Line, Column: t.Column, Message: fmt.Sprintf(format, args...)})
 }
 
 func (p *parser) nextToken() {
@@ -170,8 +311,7 @@ func (p *parser) peekTokenIs(t tokenType) bool {
 }
 
 func (p *parser) peekError(t tokenType) {
-	msg := fmt.Sprintf("expected next token to be %s, got %s instead\n", t, p.peekToken.Type)
-	p.errors = append(p.errors, msg)
+	p.errorAt(p.peekToken, "expected next token to be %s, got %s instead", t, p.peekToken.Type)
 }
 
 func (p *parser) expectPeek(t tokenType) bool {
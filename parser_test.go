@@ -390,3 +390,57 @@ func TestCompletePGN(t *testing.T) {
 	}
 
 }
+
+func TestVariations(t *testing.T) {
+	input := `[Result "*"]
+
+1. e4 e5 (1... c5 {Sicilian} 2. Nf3 (2. c3) d6) 2. Nf3 (2. Bc4 $1 {Bishop's Opening}) Nc6 *`
+
+	l := newLexer(input)
+	p := newParser(l)
+	game, _ := p.ParsePGN()
+	checkParserErrors(t, p)
+
+	first := game.GetMove(1)
+	if first.Black() != "e5" || len(first.WhiteVariations) != 0 || len(first.BlackVariations) != 1 {
+		t.Fatalf("move 1 = %q with %d white and %d black variations, want e5 with 0 and 1",
+			first.Black(), len(first.WhiteVariations), len(first.BlackVariations))
+	}
+
+	sicilian := first.BlackVariations[0]
+	if len(sicilian.Moves) != 2 {
+		t.Fatalf("Sicilian has %d moves, want 2", len(sicilian.Moves))
+	}
+	if m := sicilian.Moves[0]; m.MoveNumber != 1 || m.White() != "" || m.Black() != "c5" || len(m.BlackComments) != 1 {
+		t.Errorf("Sicilian move 1 = %+v, want 1... c5 {Sicilian}", m)
+	}
+
+	nested := sicilian.Moves[1]
+	if nested.White() != "Nf3" || nested.Black() != "d6" || len(nested.WhiteVariations) != 1 {
+		t.Fatalf("Sicilian move 2 = %+v, want 2. Nf3 (2. c3) d6", nested)
+	}
+	if m := nested.WhiteVariations[0].Moves[0]; m.White() != "c3" {
+		t.Errorf("nested variation starts with %q, want c3", m.White())
+	}
+
+	second := game.GetMove(2)
+	if second.White() != "Nf3" || second.Black() != "Nc6" || len(second.WhiteVariations) != 1 {
+		t.Fatalf("move 2 = %+v, want 2. Nf3 (2. Bc4) Nc6", second)
+	}
+	bishop := second.WhiteVariations[0].Moves[0]
+	if bishop.White() != "Bc4" || len(bishop.WhiteAnnotations) != 1 || bishop.WhiteAnnotations[0] != "1" ||
+		len(bishop.WhiteComments) != 1 || bishop.WhiteComments[0] != "Bishop's Opening" {
+		t.Errorf("Bishop's Opening variation = %+v", bishop)
+	}
+}
+
+func TestUnterminatedVariation(t *testing.T) {
+	l := newLexer("1. e4 e5\n2. Nf3 (2. Bc4 Nf6")
+	p := newParser(l)
+	p.ParsePGN()
+
+	errors := p.Errors()
+	if len(errors) != 1 || errors[0] != "2:8: unterminated variation" {
+		t.Errorf("errors = %q, want [\"2:8: unterminated variation\"]", errors)
+	}
+}
@@ -1,9 +1,18 @@
 package pgn
 
 type Game struct {
-	tags   map[string]string
-	moves  map[int]*Move
-	result string
+	tags     map[string]string
+	tagOrder []string
+	moves    map[int]*Move
+	result   string
+	comments []string
+}
+
+func newGame() *Game {
+	return &Game{
+		tags:  map[string]string{},
+		moves: map[int]*Move{},
+	}
 }
 
 func New(pgn string) (*Game, error) {
@@ -18,14 +27,47 @@ func New(pgn string) (*Game, error) {
 	return game, nil
 }
 
+// NewGames parses a PGN database holding any number of games.
+func NewGames(pgn string) ([]*Game, error) {
+	l := newLexer(pgn)
+	p := newParser(l)
+
+	return p.ParseGames()
+}
+
 func (g *Game) GetTag(name string) string {
 	return g.tags[name]
 }
 
 func (g *Game) SetTag(tag, value string) {
+	if _, ok := g.tags[tag]; !ok {
+		g.tagOrder = append(g.tagOrder, tag)
+	}
+
 	g.tags[tag] = value
 }
 
+func (g *Game) RemoveTag(tag string) {
+	if _, ok := g.tags[tag]; !ok {
+		return
+	}
+
+	delete(g.tags, tag)
+
+	for i, name := range g.tagOrder {
+		if name == tag {
+			g.tagOrder = append(g.tagOrder[:i:i], g.tagOrder[i+1:]...)
+			break
+		}
+	}
+}
+
+// TagNames returns the names of the game's tags in the order they were
+// first set.
+func (g *Game) TagNames() []string {
+	return append([]string{}, g.tagOrder...)
+}
+
 func (g *Game) TagPairs() map[string]string {
 	return g.tags
 }
@@ -74,6 +116,11 @@ func (g *Game) Moves() map[int]*Move {
 	return g.moves
 }
 
+// Comments returns the comments that appear before the first move.
+func (g *Game) Comments() []string {
+	return g.comments
+}
+
 func (g *Game) IsDraw() bool {
 	if g.result == "1/2-1/2" {
 		return true
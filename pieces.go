@@ -0,0 +1,91 @@
+package pgn
+
+import "math"
+
+// point is a coordinate in diagram units. A square is 45 units wide.
+type point struct {
+	x, y float64
+}
+
+// PieceSet draws pieces as filled outlines on a 45 by 45 unit square, with
+// y growing downwards. Each piece is a list of polygons drawn in order.
+type PieceSet struct {
+	Name   string
+	shapes map[PieceType][][]point
+	// outline is the width of the line drawn around each polygon.
+	outline float64
+}
+
+func circle(cx, cy, r float64, segments int) []point {
+	points := make([]point, segments)
+	for i := range points {
+		a := 2 * math.Pi * float64(i) / float64(segments)
+		points[i] = point{cx + r*math.Cos(a), cy + r*math.Sin(a)}
+	}
+
+	return points
+}
+
+func rect(x0, y0, x1, y1 float64) []point {
+	return []point{{x0, y0}, {x1, y0}, {x1, y1}, {x0, y1}}
+}
+
+// ClassicPieces are Staunton-like silhouettes with a dark outline.
+var ClassicPieces = &PieceSet{
+	Name:    "classic",
+	outline: 1.5,
+	shapes: map[PieceType][][]point{
+		Pawn: {
+			{{11, 39}, {34, 39}, {34, 36}, {30, 33}, {15, 33}, {11, 36}},
+			{{16, 33}, {29, 33}, {26, 24}, {19, 24}},
+			circle(22.5, 18.5, 6, 20),
+		},
+		Knight: {
+			{{10, 39}, {35, 39}, {35, 35}, {10, 35}},
+			{{13, 35}, {34, 35}, {33, 28}, {31, 20}, {28, 14}, {24, 10}, {21, 9}, {20, 5}, {17, 8}, {14, 11}, {10, 17}, {8, 23}, {9, 26}, {12, 26}, {16, 22}, {19, 21}, {18, 25}, {14, 30}},
+			circle(15.5, 14.5, 1.2, 8),
+		},
+		Bishop: {
+			{{9, 39}, {36, 39}, {36, 36}, {9, 36}},
+			{{15, 36}, {30, 36}, {28, 31}, {31, 24}, {28, 17}, {22.5, 11}, {17, 17}, {14, 24}, {17, 31}},
+			circle(22.5, 8, 2.8, 12),
+			{{21.8, 17}, {23.2, 17}, {23.2, 26}, {21.8, 26}},
+		},
+		Rook: {
+			{{9, 39}, {36, 39}, {36, 35}, {9, 35}},
+			{{13, 35}, {32, 35}, {30, 17}, {15, 17}},
+			{{11, 17}, {34, 17}, {34, 9}, {30, 9}, {30, 12}, {25, 12}, {25, 9}, {20, 9}, {20, 12}, {15, 12}, {15, 9}, {11, 9}},
+		},
+		Queen: {
+			{{10, 39}, {35, 39}, {35, 35}, {10, 35}},
+			{{12, 35}, {33, 35}, {36, 14}, {30, 26}, {29, 12}, {24, 25}, {22.5, 10}, {21, 25}, {16, 12}, {15, 26}, {9, 14}},
+			circle(9, 12, 2.2, 10),
+			circle(16, 10, 2.2, 10),
+			circle(22.5, 8, 2.2, 10),
+			circle(29, 10, 2.2, 10),
+			circle(36, 12, 2.2, 10),
+		},
+		King: {
+			{{10, 39}, {35, 39}, {35, 35}, {10, 35}},
+			{{12, 35}, {33, 35}, {37, 24}, {33, 18}, {27, 19}, {22.5, 24}, {18, 19}, {12, 18}, {8, 24}},
+			{{21, 4}, {24, 4}, {24, 7}, {27, 7}, {27, 10}, {24, 10}, {24, 17}, {21, 17}, {21, 10}, {18, 10}, {18, 7}, {21, 7}},
+		},
+	},
+}
+
+// GeometricPieces are simple flat shapes, legible at very small sizes.
+var GeometricPieces = &PieceSet{
+	Name:    "geometric",
+	outline: 1,
+	shapes: map[PieceType][][]point{
+		Pawn:   {circle(22.5, 26, 8, 24)},
+		Knight: {{{12, 36}, {33, 36}, {33, 12}, {22, 9}, {12, 18}, {20, 20}}},
+		Bishop: {{{22.5, 7}, {33, 36}, {12, 36}}},
+		Rook:   {rect(12, 11, 33, 36)},
+		Queen:  {circle(22.5, 22.5, 13, 8)},
+		King: {
+			circle(22.5, 25, 11, 24),
+			{{21, 4}, {24, 4}, {24, 8}, {28, 8}, {28, 11}, {24, 11}, {24, 15}, {21, 15}, {21, 11}, {17, 11}, {17, 8}, {21, 8}},
+		},
+	},
+}
@@ -0,0 +1,289 @@
+package pgn
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Ply is a single half-move of the game's main line together with its
+// annotations.
+type Ply struct {
+	// Index counts plies from 1 for the first move of the game, or of the
+	// variation the ply belongs to.
+	Index       int
+	MoveNumber  int
+	Color       Color
+	SAN         string
+	Annotations []string
+	Comments    []string
+	// Variations are the alternative lines to this ply.
+	Variations []*Variation
+
+	at textPosition
+}
+
+// Plies returns the moves of the game in playing order.
+func (g *Game) Plies() []Ply {
+	return pliesOf(g.sortedMoves())
+}
+
+// Plies returns the moves of the variation in playing order.
+func (v *Variation) Plies() []Ply {
+	return pliesOf(v.Moves)
+}
+
+func pliesOf(moves []*Move) []Ply {
+	plies := []Ply{}
+
+	eachPly(moves, func(c Color, m *Move) {
+		p := Ply{Index: len(plies) + 1, MoveNumber: m.MoveNumber, Color: c}
+		if c == White {
+			p.SAN, p.Annotations, p.Comments, p.Variations = m.MoveWhite, m.WhiteAnnotations, m.WhiteComments, m.WhiteVariations
+			p.at = m.whiteAt
+		} else {
+			p.SAN, p.Annotations, p.Comments, p.Variations = m.MoveBlack, m.BlackAnnotations, m.BlackComments, m.BlackVariations
+			p.at = m.blackAt
+		}
+		plies = append(plies, p)
+	})
+
+	return plies
+}
+
+// sortedMoves returns the moves of the main line ordered by move number.
+func (g *Game) sortedMoves() []*Move {
+	numbers := make([]int, 0, len(g.moves))
+	for n := range g.moves {
+		numbers = append(numbers, n)
+	}
+	sort.Ints(numbers)
+
+	moves := make([]*Move, len(numbers))
+	for i, n := range numbers {
+		moves[i] = g.moves[n]
+	}
+
+	return moves
+}
+
+// eachPly calls fn for every half-move of moves in playing order.
+func eachPly(moves []*Move, fn func(c Color, m *Move)) {
+	for _, m := range moves {
+		if m.MoveWhite != "" {
+			fn(White, m)
+		}
+		if m.MoveBlack != "" {
+			fn(Black, m)
+		}
+	}
+}
+
+// plyMove returns the move holding the given ply of the main line, counted
+// from 1, and the color that played it.
+func (g *Game) plyMove(ply int) (*Move, Color, error) {
+	var found *Move
+	var color Color
+
+	index := 0
+	eachPly(g.sortedMoves(), func(c Color, m *Move) {
+		index++
+		if index == ply {
+			found, color = m, c
+		}
+	})
+
+	if found == nil {
+		return nil, White, fmt.Errorf("ply %d out of range, game has %d plies", ply, index)
+	}
+
+	return found, color, nil
+}
+
+// plyComments returns the comment list of the given ply, counted from 1, so
+// that it can be edited in place.
+func (g *Game) plyComments(ply int) (*[]string, error) {
+	m, c, err := g.plyMove(ply)
+	if err != nil {
+		return nil, err
+	}
+
+	if c == White {
+		return &m.WhiteComments, nil
+	}
+
+	return &m.BlackComments, nil
+}
+
+// AddAnnotation adds a NAG, given by its number as in "2" for $2, to the
+// given ply unless the ply already has it.
+func (g *Game) AddAnnotation(ply int, nag string) error {
+	m, c, err := g.plyMove(ply)
+	if err != nil {
+		return err
+	}
+
+	annotations := &m.WhiteAnnotations
+	if c == Black {
+		annotations = &m.BlackAnnotations
+	}
+
+	for _, a := range *annotations {
+		if a == nag {
+			return nil
+		}
+	}
+
+	*annotations = append(*annotations, nag)
+	return nil
+}
+
+// AddVariation adds a line of SAN moves as a variation to the given ply. The
+// line is played instead of the ply, from the position before it, and is
+// checked for legality.
+func (g *Game) AddVariation(ply int, sans []string) (*Variation, error) {
+	m, c, err := g.plyMove(ply)
+	if err != nil {
+		return nil, err
+	}
+
+	pos, err := g.PositionAt(ply - 1)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := NewVariation(pos, sans)
+	if err != nil {
+		return nil, err
+	}
+
+	if c == White {
+		m.WhiteVariations = append(m.WhiteVariations, v)
+	} else {
+		m.BlackVariations = append(m.BlackVariations, v)
+	}
+
+	return v, nil
+}
+
+// NewVariation builds a variation from SAN moves played from pos. Moves are
+// rewritten in canonical SAN.
+func NewVariation(pos *Position, sans []string) (*Variation, error) {
+	v := &Variation{}
+
+	for i, san := range sans {
+		mv, err := pos.parseSAN(san)
+		if err != nil {
+			return nil, fmt.Errorf("variation move %d: %v", i+1, err)
+		}
+
+		canonical := pos.san(mv)
+		if pos.turn == White || len(v.Moves) == 0 {
+			v.Moves = append(v.Moves, &Move{
+				MoveNumber:       pos.fullmove,
+				WhiteAnnotations: []string{},
+				BlackAnnotations: []string{},
+			})
+		}
+
+		last := v.Moves[len(v.Moves)-1]
+		if pos.turn == White {
+			last.MoveWhite = canonical
+		} else {
+			last.MoveBlack = canonical
+		}
+
+		pos = pos.play(mv)
+	}
+
+	return v, nil
+}
+
+// Commands returns the command annotations embedded in the ply's comments.
+func (p Ply) Commands() []Command {
+	commands := []Command{}
+	for _, c := range p.Comments {
+		commands = append(commands, ParseCommands(c)...)
+	}
+
+	return commands
+}
+
+// Command returns the value of the named command annotation, such as "clk".
+func (p Ply) Command(name string) (string, bool) {
+	for _, c := range p.Commands() {
+		if c.Name == name {
+			return c.Value, true
+		}
+	}
+
+	return "", false
+}
+
+// Clock returns the time left on the mover's clock after the ply, from its
+// %clk annotation.
+func (p Ply) Clock() (time.Duration, bool) {
+	return p.duration("clk")
+}
+
+// ElapsedTime returns the time spent on the ply, from its %emt annotation.
+func (p Ply) ElapsedTime() (time.Duration, bool) {
+	return p.duration("emt")
+}
+
+func (p Ply) duration(name string) (time.Duration, bool) {
+	value, ok := p.Command(name)
+	if !ok {
+		return 0, false
+	}
+
+	d, err := ParseClock(value)
+	if err != nil {
+		return 0, false
+	}
+
+	return d, true
+}
+
+// TimeUsage is one point of a player's time-usage series.
+type TimeUsage struct {
+	Ply        int
+	MoveNumber int
+	// Clock is the time left after the move. HasClock is false when the
+	// move has no %clk annotation.
+	Clock    time.Duration
+	HasClock bool
+	// Elapsed is the time spent on the move, from %emt or, failing that,
+	// from the change of the clock since the player's previous move.
+	Elapsed    time.Duration
+	HasElapsed bool
+}
+
+// TimeUsage returns the clock and time spent for every move of color c.
+// Elapsed times derived from %clk add back the increment of the game's time
+// control.
+func (g *Game) TimeUsage(c Color) []TimeUsage {
+	tc, _ := g.TimeControl()
+	series := []TimeUsage{}
+
+	var previous *TimeUsage
+	for _, p := range g.Plies() {
+		if p.Color != c {
+			continue
+		}
+
+		u := TimeUsage{Ply: p.Index, MoveNumber: p.MoveNumber}
+		u.Clock, u.HasClock = p.Clock()
+		u.Elapsed, u.HasElapsed = p.ElapsedTime()
+
+		period, _ := tc.periodAt(len(series) + 1)
+		if !u.HasElapsed && u.HasClock && previous != nil && previous.HasClock && previous.Clock+period.Increment >= u.Clock {
+			u.Elapsed, u.HasElapsed = previous.Clock+period.Increment-u.Clock, true
+		}
+
+		series = append(series, u)
+		previous = &series[len(series)-1]
+	}
+
+	return series
+}
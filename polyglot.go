@@ -0,0 +1,339 @@
+package pgn
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+)
+
+const polyglotEntrySize = 16
+
+// BookEntry is a single record of a Polyglot opening book.
+type BookEntry struct {
+	Key    uint64
+	Move   uint16
+	Weight uint16
+	Learn  uint32
+}
+
+// BookMove is a book move resolved against the position it was found in.
+type BookMove struct {
+	SAN    string
+	UCI    string
+	Weight uint16
+}
+
+type PolyglotBook struct {
+	entries []BookEntry
+}
+
+func NewPolyglotBook(entries []BookEntry) *PolyglotBook {
+	b := &PolyglotBook{entries: append([]BookEntry{}, entries...)}
+	b.sort()
+	return b
+}
+
+// ReadPolyglotBook reads a Polyglot .bin book.
+func ReadPolyglotBook(r io.Reader) (*PolyglotBook, error) {
+	br := bufio.NewReader(r)
+	entries := []BookEntry{}
+	buf := make([]byte, polyglotEntrySize)
+
+	for {
+		_, err := io.ReadFull(br, buf)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, fmt.Errorf("polyglot book: truncated entry after %d entries", len(entries))
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, BookEntry{
+			Key:    binary.BigEndian.Uint64(buf[0:8]),
+			Move:   binary.BigEndian.Uint16(buf[8:10]),
+			Weight: binary.BigEndian.Uint16(buf[10:12]),
+			Learn:  binary.BigEndian.Uint32(buf[12:16]),
+		})
+	}
+
+	return NewPolyglotBook(entries), nil
+}
+
+func (b *PolyglotBook) sort() {
+	sort.SliceStable(b.entries, func(i, j int) bool {
+		if b.entries[i].Key != b.entries[j].Key {
+			return b.entries[i].Key < b.entries[j].Key
+		}
+		if b.entries[i].Weight != b.entries[j].Weight {
+			return b.entries[i].Weight > b.entries[j].Weight
+		}
+		return b.entries[i].Move < b.entries[j].Move
+	})
+}
+
+func (b *PolyglotBook) Entries() []BookEntry {
+	return b.entries
+}
+
+func (b *PolyglotBook) Len() int {
+	return len(b.entries)
+}
+
+// WriteTo writes the book in the Polyglot .bin format.
+func (b *PolyglotBook) WriteTo(w io.Writer) (int64, error) {
+	bw := bufio.NewWriter(w)
+	buf := make([]byte, polyglotEntrySize)
+	var n int64
+
+	for _, e := range b.entries {
+		binary.BigEndian.PutUint64(buf[0:8], e.Key)
+		binary.BigEndian.PutUint16(buf[8:10], e.Move)
+		binary.BigEndian.PutUint16(buf[10:12], e.Weight)
+		binary.BigEndian.PutUint32(buf[12:16], e.Learn)
+
+		written, err := bw.Write(buf)
+		n += int64(written)
+		if err != nil {
+			return n, err
+		}
+	}
+
+	return n, bw.Flush()
+}
+
+func (b *PolyglotBook) entriesFor(key uint64) []BookEntry {
+	i := sort.Search(len(b.entries), func(i int) bool {
+		return b.entries[i].Key >= key
+	})
+
+	j := i
+	for j < len(b.entries) && b.entries[j].Key == key {
+		j++
+	}
+
+	return b.entries[i:j]
+}
+
+// Lookup returns the book moves for the position, highest weight first.
+// Entries whose move is not legal in the position are skipped.
+func (b *PolyglotBook) Lookup(pos *Position) []BookMove {
+	moves := []BookMove{}
+
+	for _, e := range b.entriesFor(pos.Hash()) {
+		m, ok := pos.decodePolyglotMove(e.Move)
+		if !ok {
+			continue
+		}
+
+		moves = append(moves, BookMove{SAN: pos.san(m), UCI: pos.uci(m), Weight: e.Weight})
+	}
+
+	return moves
+}
+
+// Contains reports whether the book has the move, given in SAN, for pos.
+func (b *PolyglotBook) Contains(pos *Position, san string) bool {
+	m, err := pos.parseSAN(san)
+	if err != nil {
+		return false
+	}
+
+	code := encodePolyglotMove(m)
+	for _, e := range b.entriesFor(pos.Hash()) {
+		if e.Move == code {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Annotate replays the game and reports, for every ply, whether the move
+// played was in the book. The result has one entry per ply of the mainline.
+func (b *PolyglotBook) Annotate(g *Game) ([]bool, error) {
+	positions, err := g.Positions()
+	if err != nil {
+		return nil, err
+	}
+
+	sans := g.mainline()
+	inBook := make([]bool, len(sans))
+
+	for i, san := range sans {
+		inBook[i] = b.Contains(positions[i], san)
+	}
+
+	return inBook, nil
+}
+
+// OutOfBookPly returns the first ply of the game whose move is not in the
+// book, or 0 when the whole game is book.
+func (b *PolyglotBook) OutOfBookPly(g *Game) (int, error) {
+	inBook, err := b.Annotate(g)
+	if err != nil {
+		return 0, err
+	}
+
+	for i, ok := range inBook {
+		if !ok {
+			return i + 1, nil
+		}
+	}
+
+	return 0, nil
+}
+
+var polyglotPromotions = [...]PieceType{NoPieceType, Knight, Bishop, Rook, Queen}
+
+func encodePolyglotMove(m move) uint16 {
+	code := uint16(m.to.File()) | uint16(m.to.Rank())<<3 | uint16(m.from.File())<<6 | uint16(m.from.Rank())<<9
+
+	for i, pt := range polyglotPromotions {
+		if pt == m.promotion && pt != NoPieceType {
+			code |= uint16(i) << 12
+		}
+	}
+
+	return code
+}
+
+func (pos *Position) decodePolyglotMove(code uint16) (move, bool) {
+	to := newSquare(int(code&7), int(code>>3&7))
+	from := newSquare(int(code>>6&7), int(code>>9&7))
+
+	promo := int(code >> 12 & 7)
+	if promo >= len(polyglotPromotions) {
+		return move{}, false
+	}
+	promotion := polyglotPromotions[promo]
+
+	for _, m := range pos.legalMoves() {
+		if m.from == from && m.to == to && m.promotion == promotion {
+			return m, true
+		}
+	}
+
+	return move{}, false
+}
+
+// BookOptions controls how BuildPolyglotBook scores moves.
+type BookOptions struct {
+	// MaxPly limits how deep into each game moves are collected. Zero means
+	// the whole game.
+	MaxPly int
+	// MinGames drops moves played in fewer games.
+	MinGames int
+	// Points credited to a move for a win, draw or loss of the side that
+	// played it.
+	WinPoints  int
+	DrawPoints int
+	LossPoints int
+}
+
+// DefaultBookOptions scores moves like Polyglot's make-book: two points for
+// a win and one for a draw.
+var DefaultBookOptions = BookOptions{
+	MaxPly:     40,
+	MinGames:   1,
+	WinPoints:  2,
+	DrawPoints: 1,
+	LossPoints: 0,
+}
+
+type bookKey struct {
+	key  uint64
+	move uint16
+}
+
+type bookScore struct {
+	games  int
+	points int
+}
+
+// BuildPolyglotBook builds a book from the mainlines of the games. The weight
+// of each move is the total of the points its side scored with it, and moves
+// that score no points are left out.
+func BuildPolyglotBook(games []*Game, opts BookOptions) (*PolyglotBook, error) {
+	scores := map[bookKey]*bookScore{}
+
+	for gi, g := range games {
+		positions, err := g.Positions()
+		if err != nil {
+			return nil, fmt.Errorf("game %d: %v", gi+1, err)
+		}
+
+		sans := g.mainline()
+		for i, san := range sans {
+			if opts.MaxPly > 0 && i >= opts.MaxPly {
+				break
+			}
+
+			pos := positions[i]
+			m, err := pos.parseSAN(san)
+			if err != nil {
+				return nil, fmt.Errorf("game %d: ply %d: %v", gi+1, i+1, err)
+			}
+
+			k := bookKey{key: pos.Hash(), move: encodePolyglotMove(m)}
+			s, ok := scores[k]
+			if !ok {
+				s = &bookScore{}
+				scores[k] = s
+			}
+
+			s.games++
+			s.points += resultPoints(g.Result(), pos.Turn(), opts)
+		}
+	}
+
+	maxPoints := 0
+	for _, s := range scores {
+		if s.points > maxPoints {
+			maxPoints = s.points
+		}
+	}
+
+	entries := []BookEntry{}
+	for k, s := range scores {
+		if s.games < opts.MinGames || s.points <= 0 {
+			continue
+		}
+
+		weight := s.points
+		if maxPoints > 0xffff {
+			weight = s.points * 0xffff / maxPoints
+			if weight == 0 {
+				weight = 1
+			}
+		}
+
+		entries = append(entries, BookEntry{Key: k.key, Move: k.move, Weight: uint16(weight)})
+	}
+
+	return NewPolyglotBook(entries), nil
+}
+
+func resultPoints(result string, side Color, opts BookOptions) int {
+	switch result {
+	case "1/2-1/2":
+		return opts.DrawPoints
+	case "1-0":
+		if side == White {
+			return opts.WinPoints
+		}
+		return opts.LossPoints
+	case "0-1":
+		if side == Black {
+			return opts.WinPoints
+		}
+		return opts.LossPoints
+	default:
+		return 0
+	}
+}
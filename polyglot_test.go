@@ -0,0 +1,136 @@
+package pgn
+
+import (
+	"bytes"
+	"testing"
+)
+
+const bookGames = `[Event "A"]
+[Result "1-0"]
+
+1. e4 e5 2. Nf3 Nc6 3. Bb5 1-0
+
+[Event "B"]
+[Result "1/2-1/2"]
+
+1. e4 c5 2. Nf3 d6 1/2-1/2
+
+[Event "C"]
+[Result "0-1"]
+
+1. d4 d5 2. c4 e6 0-1
+
+[Event "D"]
+[Result "1-0"]
+
+1. e4 e5 2. Nf3 Nf6 1-0`
+
+func TestBuildPolyglotBook(t *testing.T) {
+	games, err := NewGames(bookGames)
+	if err != nil {
+		t.Fatalf("NewGames() error: %v", err)
+	}
+
+	book, err := BuildPolyglotBook(games, DefaultBookOptions)
+	if err != nil {
+		t.Fatalf("BuildPolyglotBook() error: %v", err)
+	}
+
+	moves := book.Lookup(StartingPosition())
+	expected := []BookMove{
+		{SAN: "e4", UCI: "e2e4", Weight: 5},
+	}
+
+	if len(moves) != len(expected) {
+		t.Fatalf("Lookup(start) = %v, want %v", moves, expected)
+	}
+	for i := range expected {
+		if moves[i] != expected[i] {
+			t.Errorf("Lookup(start)[%d] = %v, want %v", i, moves[i], expected[i])
+		}
+	}
+
+	var buf bytes.Buffer
+	n, err := book.WriteTo(&buf)
+	if err != nil {
+		t.Fatalf("WriteTo() error: %v", err)
+	}
+	if n != int64(book.Len()*polyglotEntrySize) {
+		t.Errorf("WriteTo() wrote %d bytes, want %d", n, book.Len()*polyglotEntrySize)
+	}
+
+	read, err := ReadPolyglotBook(&buf)
+	if err != nil {
+		t.Fatalf("ReadPolyglotBook() error: %v", err)
+	}
+
+	if read.Len() != book.Len() {
+		t.Fatalf("ReadPolyglotBook() read %d entries, want %d", read.Len(), book.Len())
+	}
+	for i, e := range book.Entries() {
+		if read.Entries()[i] != e {
+			t.Errorf("entry %d = %v, want %v", i, read.Entries()[i], e)
+		}
+	}
+}
+
+func TestPolyglotBookAnnotate(t *testing.T) {
+	games, err := NewGames(bookGames)
+	if err != nil {
+		t.Fatalf("NewGames() error: %v", err)
+	}
+
+	opts := DefaultBookOptions
+	opts.LossPoints = 1
+
+	book, err := BuildPolyglotBook(games[:1], opts)
+	if err != nil {
+		t.Fatalf("BuildPolyglotBook() error: %v", err)
+	}
+
+	inBook, err := book.Annotate(games[3])
+	if err != nil {
+		t.Fatalf("Annotate() error: %v", err)
+	}
+
+	expected := []bool{true, true, true, false}
+	if len(inBook) != len(expected) {
+		t.Fatalf("Annotate() = %v, want %v", inBook, expected)
+	}
+	for i := range expected {
+		if inBook[i] != expected[i] {
+			t.Errorf("Annotate()[%d] = %v, want %v", i, inBook[i], expected[i])
+		}
+	}
+
+	ply, err := book.OutOfBookPly(games[3])
+	if err != nil {
+		t.Fatalf("OutOfBookPly() error: %v", err)
+	}
+	if ply != 4 {
+		t.Errorf("OutOfBookPly() = %d, want 4", ply)
+	}
+}
+
+func TestPolyglotCastlingEncoding(t *testing.T) {
+	pos, err := ParseFEN("r3k2r/8/8/8/8/8/8/R3K2R w KQkq - 0 1")
+	if err != nil {
+		t.Fatalf("ParseFEN() error: %v", err)
+	}
+
+	m, err := pos.parseSAN("O-O")
+	if err != nil {
+		t.Fatalf("parseSAN() error: %v", err)
+	}
+
+	// Polyglot encodes castling as the king capturing its own rook: e1h1.
+	if got, want := encodePolyglotMove(m), uint16(0x0107); got != want {
+		t.Errorf("encodePolyglotMove(O-O) = %#04x, want %#04x", got, want)
+	}
+
+	book := NewPolyglotBook([]BookEntry{{Key: pos.Hash(), Move: 0x0107, Weight: 1}})
+	moves := book.Lookup(pos)
+	if len(moves) != 1 || moves[0].SAN != "O-O" || moves[0].UCI != "e1g1" {
+		t.Errorf("Lookup() = %v, want O-O", moves)
+	}
+}
@@ -0,0 +1,169 @@
+package pgn
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"sort"
+)
+
+// Image draws the position after the given ply as a raster image, with the
+// same drawing as SVG.
+func (g *Game) Image(ply int, opts DiagramOptions) (*image.RGBA, error) {
+	opts = opts.withDefaults()
+
+	d, err := g.diagram(ply, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return d.rasterize(opts.Size), nil
+}
+
+// Image draws the position as a raster image.
+func (pos *Position) Image(opts DiagramOptions) *image.RGBA {
+	opts = opts.withDefaults()
+	return pos.diagram(opts, diagramMarks{from: NoSquare, to: NoSquare}).rasterize(opts.Size)
+}
+
+// rasterize draws the diagram onto a size by size image. Shapes are filled
+// without antialiasing so that frames keep a small palette.
+func (d *diagram) rasterize(size int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	scale := float64(size) / (8 * squareSize)
+
+	for _, s := range d.shapes {
+		fillPolygon(img, scale, s.contours, s.fill)
+		if s.strokeWidth > 0 {
+			for _, contour := range s.contours {
+				strokePolygon(img, scale, contour, s.strokeWidth, s.stroke)
+			}
+		}
+	}
+
+	for _, l := range d.labels {
+		drawLabel(img, scale, l)
+	}
+
+	return img
+}
+
+// fillPolygon fills the contours with the even-odd rule, sampling every
+// pixel at its center.
+func fillPolygon(img *image.RGBA, scale float64, contours [][]point, c color.RGBA) {
+	minY, maxY := math.Inf(1), math.Inf(-1)
+	for _, contour := range contours {
+		for _, p := range contour {
+			minY, maxY = math.Min(minY, p.y*scale), math.Max(maxY, p.y*scale)
+		}
+	}
+
+	bounds := img.Bounds()
+	top := max(bounds.Min.Y, int(math.Floor(minY)))
+	bottom := min(bounds.Max.Y, int(math.Ceil(maxY)))
+
+	var crossings []float64
+	for y := top; y < bottom; y++ {
+		sy := float64(y) + 0.5
+		crossings = crossings[:0]
+
+		for _, contour := range contours {
+			for i, a := range contour {
+				b := contour[(i+1)%len(contour)]
+				ay, by := a.y*scale, b.y*scale
+				if (ay <= sy) == (by <= sy) {
+					continue
+				}
+				t := (sy - ay) / (by - ay)
+				crossings = append(crossings, (a.x+t*(b.x-a.x))*scale)
+			}
+		}
+
+		sort.Float64s(crossings)
+		for i := 0; i+1 < len(crossings); i += 2 {
+			left := max(bounds.Min.X, int(math.Ceil(crossings[i]-0.5)))
+			right := min(bounds.Max.X, int(math.Ceil(crossings[i+1]-0.5)))
+			for x := left; x < right; x++ {
+				blend(img, x, y, c)
+			}
+		}
+	}
+}
+
+// strokePolygon draws the outline of a contour as a quad along every edge
+// and a small disc on every corner.
+func strokePolygon(img *image.RGBA, scale float64, contour []point, width float64, c color.RGBA) {
+	half := width / 2
+
+	for i, a := range contour {
+		b := contour[(i+1)%len(contour)]
+		length := math.Hypot(b.x-a.x, b.y-a.y)
+		if length == 0 {
+			continue
+		}
+
+		nx, ny := -(b.y-a.y)/length*half, (b.x-a.x)/length*half
+		quad := []point{{a.x + nx, a.y + ny}, {b.x + nx, b.y + ny}, {b.x - nx, b.y - ny}, {a.x - nx, a.y - ny}}
+		fillPolygon(img, scale, [][]point{quad}, c)
+		fillPolygon(img, scale, [][]point{circle(a.x, a.y, half, 8)}, c)
+	}
+}
+
+// blend draws c over the pixel at x, y.
+func blend(img *image.RGBA, x, y int, c color.RGBA) {
+	if c.A == 0xff {
+		img.SetRGBA(x, y, c)
+		return
+	}
+
+	under := img.RGBAAt(x, y)
+	a := uint32(c.A)
+	mix := func(top, bottom uint8) uint8 {
+		return uint8((uint32(top)*a + uint32(bottom)*(0xff-a) + 0x7f) / 0xff)
+	}
+
+	img.SetRGBA(x, y, color.RGBA{mix(c.R, under.R), mix(c.G, under.G), mix(c.B, under.B), 0xff})
+}
+
+// labelGlyphs is a 3 by 5 pixel font covering the coordinate labels.
+var labelGlyphs = map[rune][5]string{
+	'a': {"###", "..#", "###", "#.#", "###"},
+	'b': {"#..", "#..", "###", "#.#", "###"},
+	'c': {"...", "###", "#..", "#..", "###"},
+	'd': {"..#", "..#", "###", "#.#", "###"},
+	'e': {"###", "#.#", "###", "#..", "###"},
+	'f': {".##", "#..", "###", "#..", "#.."},
+	'g': {"###", "#.#", "###", "..#", "##."},
+	'h': {"#..", "#..", "###", "#.#", "#.#"},
+	'1': {".#.", "##.", ".#.", ".#.", "###"},
+	'2': {"###", "..#", "###", "#..", "###"},
+	'3': {"###", "..#", ".##", "..#", "###"},
+	'4': {"#.#", "#.#", "###", "..#", "..#"},
+	'5': {"###", "#..", "###", "..#", "###"},
+	'6': {"###", "#..", "###", "#.#", "###"},
+	'7': {"###", "..#", "..#", "..#", "..#"},
+	'8': {"###", "#.#", "###", "#.#", "###"},
+}
+
+// drawLabel draws a label with the bitmap font, five font pixels tall and
+// standing on the label's baseline.
+func drawLabel(img *image.RGBA, scale float64, l label) {
+	pixel := l.size / 5
+	x := l.x
+
+	for _, r := range l.text {
+		glyph, ok := labelGlyphs[r]
+		if ok {
+			for row, line := range glyph {
+				for col, ch := range line {
+					if ch != '#' {
+						continue
+					}
+					px, py := x+float64(col)*pixel, l.y-float64(5-row)*pixel
+					fillPolygon(img, scale, [][]point{rect(px, py, px+pixel, py+pixel)}, l.color)
+				}
+			}
+		}
+		x += 4 * pixel
+	}
+}
@@ -0,0 +1,127 @@
+package pgn
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// Reader reads the games of a PGN database one at a time, so that large
+// databases need not be held in memory.
+type Reader struct {
+	r       *bufio.Reader
+	pending []*Game
+	// carry is the first line of the next game, read while looking for the
+	// end of the current one.
+	carry     string
+	line      int
+	carryLine int
+	done      bool
+}
+
+func NewReader(r io.Reader) *Reader {
+	return &Reader{r: bufio.NewReader(r)}
+}
+
+// Next returns the next game, or io.EOF after the last one. A game that fails
+// to parse is reported by its first *SyntaxError, with the line counted from
+// the start of the input, and reading can go on with the following game. An
+// error from the underlying reader is returned once, and Next returns io.EOF
+// after it.
+func (r *Reader) Next() (*Game, error) {
+	for len(r.pending) == 0 {
+		if r.done && r.carry == "" {
+			return nil, io.EOF
+		}
+
+		chunk, start, err := r.readChunk()
+		if err != nil {
+			return nil, err
+		}
+
+		p := newParser(newLexer(chunk))
+		games, err := p.ParseGames()
+		if err != nil {
+			first := *p.errors[0]
+			first.Line += start - 1
+			return nil, &first
+		}
+		r.pending = games
+	}
+
+	g := r.pending[0]
+	r.pending = r.pending[1:]
+	return g, nil
+}
+
+// readChunk reads the text of one game: lines up to the next tag pair that
+// follows movetext. Tag-like lines inside brace comments do not count.
+func (r *Reader) readChunk() (string, int, error) {
+	var sb strings.Builder
+	start := r.carryLine
+	if r.carry != "" {
+		sb.WriteString(r.carry)
+		r.carry = ""
+	} else {
+		start = r.line + 1
+	}
+
+	inComment, hasMovetext := false, false
+	if sb.Len() > 0 {
+		inComment, hasMovetext = scanLine(sb.String(), false)
+	}
+
+	for !r.done {
+		line, err := r.r.ReadString('\n')
+		if err == io.EOF {
+			r.done = true
+		} else if err != nil {
+			r.done, r.carry = true, ""
+			return "", 0, err
+		}
+		if line == "" {
+			break
+		}
+		r.line++
+
+		if !inComment && hasMovetext && strings.HasPrefix(strings.TrimSpace(line), "[") {
+			r.carry, r.carryLine = line, r.line
+			break
+		}
+
+		sb.WriteString(line)
+
+		var movetext bool
+		inComment, movetext = scanLine(line, inComment)
+		hasMovetext = hasMovetext || movetext
+	}
+
+	return sb.String(), start, nil
+}
+
+// scanLine reports whether a line ends inside a brace comment and whether it
+// holds anything other than tag pairs.
+func scanLine(line string, inComment bool) (bool, bool) {
+	trimmed := strings.TrimSpace(line)
+	movetext := inComment || (trimmed != "" && trimmed[0] != '[' && trimmed[0] != '%')
+
+	inString := false
+	for i := 0; i < len(line); i++ {
+		ch := line[i]
+		switch {
+		case inComment:
+			inComment = ch != '}'
+		case inString && ch == '\\':
+			i++
+		case ch == '"' && !movetext:
+			inString = !inString
+		case inString:
+		case ch == '{':
+			inComment = true
+		case ch == ';':
+			return false, movetext
+		}
+	}
+
+	return inComment, movetext
+}
@@ -0,0 +1,56 @@
+package pgn
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestReader(t *testing.T) {
+	input := `[Event "One"]
+[Result "1-0"]
+
+1. e4 {a comment that mentions
+[Event "Fake"] on its own line} e5 1-0
+[Event "Two"]
+[Result "*"]
+
+1. d4 *
+
+[Event "Broken"]
+[Result "1-0"]
+
+1. e4 ( e5
+
+[Event "Three"] [Result "0-1"] 1. c4 0-1`
+
+	r := NewReader(strings.NewReader(input))
+	events := []string{}
+	errors := 0
+
+	for {
+		g, err := r.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			errors++
+			if err.Error() != "14:7: unterminated variation" {
+				t.Errorf("Next() error = %v, want an unterminated variation at 14:7", err)
+			}
+			continue
+		}
+		events = append(events, g.Event())
+	}
+
+	if strings.Join(events, ",") != "One,Two,Three" {
+		t.Errorf("Next() read %v, want [One Two Three]", events)
+	}
+	if errors != 1 {
+		t.Errorf("Next() returned %d errors, want 1", errors)
+	}
+
+	if _, err := NewReader(strings.NewReader("")).Next(); err != io.EOF {
+		t.Errorf("Next() of empty input = %v, want io.EOF", err)
+	}
+}
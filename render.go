@@ -0,0 +1,50 @@
+package pgn
+
+import "fmt"
+
+// boardMove returns where a move takes the moving piece from and to, as a
+// board diagram shows it. Castling goes to the king's destination rather
+// than onto the rook, and drops come from NoSquare.
+func (pos *Position) boardMove(m move) (Square, Square) {
+	if m.drop != NoPieceType {
+		return NoSquare, m.to
+	}
+
+	if m.castling {
+		side := queenSide
+		if m.to.File() > m.from.File() {
+			side = kingSide
+		}
+
+		kingTo, _ := castlingDestinations(pos.turn, side)
+		return m.from, kingTo
+	}
+
+	return m.from, m.to
+}
+
+// renderState returns the position after the given ply with the squares of
+// the move that reached it. Both squares are NoSquare before the first move.
+func (g *Game) renderState(ply int) (*Position, Square, Square, error) {
+	positions, err := g.Positions()
+	if err != nil {
+		return nil, NoSquare, NoSquare, err
+	}
+
+	if ply < 0 || ply >= len(positions) {
+		return nil, NoSquare, NoSquare, fmt.Errorf("ply %d out of range, game has %d plies", ply, len(positions)-1)
+	}
+
+	if ply == 0 {
+		return positions[0], NoSquare, NoSquare, nil
+	}
+
+	before := positions[ply-1]
+	m, err := before.parseSAN(g.Plies()[ply-1].SAN)
+	if err != nil {
+		return nil, NoSquare, NoSquare, err
+	}
+
+	from, to := before.boardMove(m)
+	return positions[ply], from, to, nil
+}
@@ -0,0 +1,74 @@
+package pgn
+
+import "fmt"
+
+// StartingPosition returns the position the game starts from, taken from
+// the FEN tag when present. The position carries the rules of the game's
+// variant, and Chess960 games are replayed with Chess960 castling rules.
+func (g *Game) StartingPosition() (*Position, error) {
+	v, err := g.Variant()
+	if err != nil {
+		return nil, err
+	}
+
+	fen := g.GetTag("FEN")
+	if fen == "" {
+		fen = v.StartingFEN()
+	}
+
+	pos, err := ParseVariantFEN(fen, v)
+	if err != nil {
+		return nil, err
+	}
+
+	return pos, nil
+}
+
+// mainline returns the moves of the game in playing order.
+func (g *Game) mainline() []string {
+	sans := []string{}
+	for _, p := range g.Plies() {
+		sans = append(sans, p.SAN)
+	}
+
+	return sans
+}
+
+// Positions replays the game and returns every position reached, starting
+// with the initial position. Positions()[n] is the position after n plies.
+func (g *Game) Positions() ([]*Position, error) {
+	pos, err := g.StartingPosition()
+	if err != nil {
+		return nil, err
+	}
+
+	sans := g.mainline()
+	positions := make([]*Position, 0, len(sans)+1)
+	positions = append(positions, pos)
+
+	for i, san := range sans {
+		next, err := pos.PlaySAN(san)
+		if err != nil {
+			return nil, fmt.Errorf("ply %d: %v", i+1, err)
+		}
+
+		positions = append(positions, next)
+		pos = next
+	}
+
+	return positions, nil
+}
+
+// PositionAt returns the position after the given number of plies.
+func (g *Game) PositionAt(ply int) (*Position, error) {
+	positions, err := g.Positions()
+	if err != nil {
+		return nil, err
+	}
+
+	if ply < 0 || ply >= len(positions) {
+		return nil, fmt.Errorf("ply %d out of range, game has %d plies", ply, len(positions)-1)
+	}
+
+	return positions[ply], nil
+}
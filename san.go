@@ -0,0 +1,355 @@
+package pgn
+
+import (
+	"fmt"
+	"strings"
+)
+
+func (pos *Position) PlaySAN(san string) (*Position, error) {
+	m, err := pos.parseSAN(san)
+	if err != nil {
+		return nil, err
+	}
+
+	return pos.play(m), nil
+}
+
+func (pos *Position) PlayUCI(uci string) (*Position, error) {
+	m, err := pos.parseUCI(uci)
+	if err != nil {
+		return nil, err
+	}
+
+	return pos.play(m), nil
+}
+
+// PlayLine plays a line of SAN moves, such as "1. e4 c5 2. Nf3", from the
+// position. Move numbers are ignored.
+func (pos *Position) PlayLine(line string) (*Position, error) {
+	for _, field := range strings.Fields(line) {
+		san := strings.TrimLeft(field, "0123456789.")
+		if san == "" {
+			continue
+		}
+
+		next, err := pos.PlaySAN(san)
+		if err != nil {
+			return nil, err
+		}
+		pos = next
+	}
+
+	return pos, nil
+}
+
+// LegalMoves returns every legal move in the position in SAN.
+func (pos *Position) LegalMoves() []string {
+	moves := pos.legalMoves()
+	sans := make([]string, 0, len(moves))
+
+	for _, m := range moves {
+		sans = append(sans, pos.san(m))
+	}
+
+	return sans
+}
+
+func (pos *Position) SANToUCI(san string) (string, error) {
+	m, err := pos.parseSAN(san)
+	if err != nil {
+		return "", err
+	}
+
+	return pos.uci(m), nil
+}
+
+func (pos *Position) UCIToSAN(uci string) (string, error) {
+	m, err := pos.parseUCI(uci)
+	if err != nil {
+		return "", err
+	}
+
+	return pos.san(m), nil
+}
+
+func isCastlingSAN(san string) (bool, int) {
+	switch san {
+	case "O-O", "0-0":
+		return true, kingSide
+	case "O-O-O", "0-0-0":
+		return true, queenSide
+	default:
+		return false, 0
+	}
+}
+
+func stripSANSuffix(san string) string {
+	san = strings.TrimSuffix(san, "e.p.")
+	return strings.TrimRight(san, "+#!?")
+}
+
+func (pos *Position) parseSAN(san string) (move, error) {
+	s := stripSANSuffix(san)
+	legal := pos.legalMoves()
+
+	if ok, side := isCastlingSAN(s); ok {
+		for _, m := range legal {
+			if m.castling && pos.castlingSide(m) == side {
+				return m, nil
+			}
+		}
+		return move{}, fmt.Errorf("illegal move %q in position %s", san, pos.FEN())
+	}
+
+	if len(s) < 2 {
+		return move{}, fmt.Errorf("invalid move %q", san)
+	}
+
+	if strings.IndexByte(s, '@') >= 0 {
+		return pos.parseDrop(san, s, legal)
+	}
+
+	pieceType := Pawn
+	if pt := pieceTypeFromLetter(s[0]); pt != NoPieceType && pt != Pawn && s[0] >= 'A' && s[0] <= 'Z' {
+		pieceType = pt
+		s = s[1:]
+	}
+
+	promotion := NoPieceType
+	if i := strings.IndexByte(s, '='); i >= 0 {
+		if i+2 != len(s) {
+			return move{}, fmt.Errorf("invalid promotion in move %q", san)
+		}
+		promotion = pieceTypeFromLetter(s[i+1])
+		s = s[:i]
+	} else if pieceType == Pawn && len(s) > 2 && strings.IndexByte("NBRQ", s[len(s)-1]) >= 0 {
+		promotion = pieceTypeFromLetter(s[len(s)-1])
+		s = s[:len(s)-1]
+	}
+
+	if len(s) < 2 {
+		return move{}, fmt.Errorf("invalid move %q", san)
+	}
+
+	to, err := ParseSquare(s[len(s)-2:])
+	if err != nil {
+		return move{}, fmt.Errorf("invalid move %q: %v", san, err)
+	}
+
+	fromFile, fromRank := -1, -1
+	for _, ch := range []byte(s[:len(s)-2]) {
+		switch {
+		case ch >= 'a' && ch <= 'h':
+			fromFile = int(ch - 'a')
+		case ch >= '1' && ch <= '8':
+			fromRank = int(ch - '1')
+		case ch == 'x' || ch == '-' || ch == ':':
+		default:
+			return move{}, fmt.Errorf("invalid move %q", san)
+		}
+	}
+
+	var found []move
+	for _, m := range legal {
+		if m.castling || m.drop != NoPieceType || m.to != to || pos.board[m.from].Type != pieceType || m.promotion != promotion {
+			continue
+		}
+		if fromFile >= 0 && m.from.File() != fromFile {
+			continue
+		}
+		if fromRank >= 0 && m.from.Rank() != fromRank {
+			continue
+		}
+		found = append(found, m)
+	}
+
+	switch len(found) {
+	case 0:
+		return move{}, fmt.Errorf("illegal move %q in position %s", san, pos.FEN())
+	case 1:
+		return found[0], nil
+	default:
+		return move{}, fmt.Errorf("ambiguous move %q in position %s", san, pos.FEN())
+	}
+}
+
+// parseDrop parses a crazyhouse drop such as "N@f3", or "@e4" and "P@e4" for
+// pawns.
+func (pos *Position) parseDrop(san, s string, legal []move) (move, error) {
+	piece, square, _ := strings.Cut(s, "@")
+
+	pieceType := Pawn
+	if piece != "" {
+		pieceType = pieceTypeFromLetter(piece[0])
+		if len(piece) != 1 || piece[0] < 'A' || piece[0] > 'Z' || pieceType == NoPieceType || pieceType == King {
+			return move{}, fmt.Errorf("invalid drop %q", san)
+		}
+	}
+
+	to, err := ParseSquare(square)
+	if err != nil {
+		return move{}, fmt.Errorf("invalid drop %q: %v", san, err)
+	}
+
+	for _, m := range legal {
+		if m.drop == pieceType && m.to == to {
+			return m, nil
+		}
+	}
+
+	return move{}, fmt.Errorf("illegal drop %q in position %s", san, pos.FEN())
+}
+
+func (pos *Position) castlingSide(m move) int {
+	if m.to.File() > m.from.File() {
+		return kingSide
+	}
+
+	return queenSide
+}
+
+func (pos *Position) san(m move) string {
+	var sb strings.Builder
+
+	if m.drop != NoPieceType {
+		if m.drop != Pawn {
+			sb.WriteString(m.drop.Letter())
+		}
+		sb.WriteByte('@')
+		sb.WriteString(m.to.String())
+	} else if m.castling {
+		if pos.castlingSide(m) == kingSide {
+			sb.WriteString("O-O")
+		} else {
+			sb.WriteString("O-O-O")
+		}
+	} else {
+		piece := pos.board[m.from]
+		capture := !pos.board[m.to].IsEmpty() || (piece.Type == Pawn && m.from.File() != m.to.File())
+
+		if piece.Type == Pawn {
+			if capture {
+				sb.WriteByte(byte('a' + m.from.File()))
+			}
+		} else {
+			sb.WriteString(piece.Type.Letter())
+			sb.WriteString(pos.disambiguation(m))
+		}
+
+		if capture {
+			sb.WriteByte('x')
+		}
+
+		sb.WriteString(m.to.String())
+
+		if m.promotion != NoPieceType {
+			sb.WriteByte('=')
+			sb.WriteString(m.promotion.Letter())
+		}
+	}
+
+	next := pos.play(m)
+	if next.InCheck() {
+		if len(next.legalMoves()) == 0 {
+			sb.WriteByte('#')
+		} else {
+			sb.WriteByte('+')
+		}
+	}
+
+	return sb.String()
+}
+
+func (pos *Position) disambiguation(m move) string {
+	pieceType := pos.board[m.from].Type
+	ambiguous, sameFile, sameRank := false, false, false
+
+	for _, other := range pos.legalMoves() {
+		if other.castling || other.drop != NoPieceType || other.from == m.from || other.to != m.to || pos.board[other.from].Type != pieceType {
+			continue
+		}
+
+		ambiguous = true
+		if other.from.File() == m.from.File() {
+			sameFile = true
+		}
+		if other.from.Rank() == m.from.Rank() {
+			sameRank = true
+		}
+	}
+
+	switch {
+	case !ambiguous:
+		return ""
+	case !sameFile:
+		return string(byte('a' + m.from.File()))
+	case !sameRank:
+		return string(byte('1' + m.from.Rank()))
+	default:
+		return m.from.String()
+	}
+}
+
+func (pos *Position) uci(m move) string {
+	if m.drop != NoPieceType {
+		return m.drop.Letter() + "@" + m.to.String()
+	}
+
+	to := m.to
+	if m.castling && !pos.chess960 {
+		to, _ = castlingDestinations(pos.turn, pos.castlingSide(m))
+	}
+
+	s := m.from.String() + to.String()
+	if m.promotion != NoPieceType {
+		s += strings.ToLower(m.promotion.Letter())
+	}
+
+	return s
+}
+
+func (pos *Position) parseUCI(uci string) (move, error) {
+	if len(uci) != 4 && len(uci) != 5 {
+		return move{}, fmt.Errorf("invalid UCI move %q", uci)
+	}
+
+	if uci[1] == '@' {
+		return pos.parseDrop(uci, uci, pos.legalMoves())
+	}
+
+	from, err := ParseSquare(uci[0:2])
+	if err != nil {
+		return move{}, fmt.Errorf("invalid UCI move %q: %v", uci, err)
+	}
+
+	to, err := ParseSquare(uci[2:4])
+	if err != nil {
+		return move{}, fmt.Errorf("invalid UCI move %q: %v", uci, err)
+	}
+
+	promotion := NoPieceType
+	if len(uci) == 5 {
+		promotion = pieceTypeFromLetter(uci[4])
+		if promotion == NoPieceType || promotion == Pawn || promotion == King {
+			return move{}, fmt.Errorf("invalid UCI move %q: bad promotion", uci)
+		}
+	}
+
+	for _, m := range pos.legalMoves() {
+		if m.from != from || m.promotion != promotion {
+			continue
+		}
+
+		if m.to == to {
+			return m, nil
+		}
+
+		if m.castling {
+			if kingTo, _ := castlingDestinations(pos.turn, pos.castlingSide(m)); kingTo == to {
+				return m, nil
+			}
+		}
+	}
+
+	return move{}, fmt.Errorf("illegal UCI move %q in position %s", uci, pos.FEN())
+}
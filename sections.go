@@ -1,6 +1,9 @@
 package pgn
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 type stmt interface {
 	Type() string
@@ -14,6 +17,20 @@ type Move struct {
 	MoveBlack        string
 	WhiteAnnotations []string
 	BlackAnnotations []string
+	WhiteComments    []string
+	BlackComments    []string
+	WhiteVariations  []*Variation
+	BlackVariations  []*Variation
+
+	// whiteAt and blackAt are where the moves were read from.
+	whiteAt textPosition
+	blackAt textPosition
+}
+
+// textPosition is a line and column of PGN text, counted from 1. The zero
+// value stands for moves that were not parsed.
+type textPosition struct {
+	line, column int
 }
 
 func (m Move) Number() int {
@@ -44,6 +61,47 @@ func (m Move) GetAnnotations(color string) []string {
 	return []string{}
 }
 
+// GetComments returns the comments that follow the move of the given color.
+func (m Move) GetComments(color string) []string {
+	if color == "White" {
+		return m.WhiteComments
+	}
+
+	if color == "Black" {
+		return m.BlackComments
+	}
+
+	return []string{}
+}
+
+// GetVariations returns the variations that replace the move of the given
+// color.
+func (m Move) GetVariations(color string) []*Variation {
+	if color == "White" {
+		return m.WhiteVariations
+	}
+
+	if color == "Black" {
+		return m.BlackVariations
+	}
+
+	return []*Variation{}
+}
+
+// IsDrop reports whether the move of the given color drops a piece from the
+// pocket, as in crazyhouse ("N@f3").
+func (m Move) IsDrop(color string) bool {
+	if color == "White" {
+		return strings.Contains(m.MoveWhite, "@")
+	}
+
+	if color == "Black" {
+		return strings.Contains(m.MoveBlack, "@")
+	}
+
+	return false
+}
+
 func (m Move) String() string {
 	return fmt.Sprintf("%d. %s %s", m.MoveNumber, m.MoveWhite, m.MoveBlack)
 }
@@ -86,3 +144,78 @@ func (gt gameTermination) Value() string {
 func (gt gameTermination) Type() string {
 	return TERMINATION
 }
+
+// Comment
+
+type comment struct {
+	Text string
+}
+
+func (c comment) Type() string {
+	return COMMENT_TEXT
+}
+
+// Variation
+
+// Variation is a recursive annotation variation: a line of moves played
+// instead of the move it follows, with the comments that precede its first
+// move.
+type Variation struct {
+	Comments []string
+	Moves    []*Move
+}
+
+func (v Variation) Type() string {
+	return VARIATION
+}
+
+// addMove appends m to the line. A black move that repeats the number of the
+// white move it answers, as in "1. e4 {comment} 1... e5", is merged into
+// that move. It returns the move as stored.
+func (v *Variation) addMove(m *Move) *Move {
+	if n := len(v.Moves); n > 0 {
+		prev := v.Moves[n-1]
+		if prev.MoveNumber == m.MoveNumber && prev.MoveBlack == "" && m.MoveWhite == "" {
+			prev.MoveBlack = m.MoveBlack
+			prev.BlackAnnotations = m.BlackAnnotations
+			prev.BlackComments = m.BlackComments
+			prev.BlackVariations = m.BlackVariations
+			prev.blackAt = m.blackAt
+			return prev
+		}
+	}
+
+	v.Moves = append(v.Moves, m)
+	return m
+}
+
+// addComment attaches a comment to the last move of the line, or to the line
+// itself before its first move.
+func (v *Variation) addComment(text string) {
+	if len(v.Moves) == 0 {
+		v.Comments = append(v.Comments, text)
+		return
+	}
+
+	last := v.Moves[len(v.Moves)-1]
+	if last.MoveBlack != "" {
+		last.BlackComments = append(last.BlackComments, text)
+	} else {
+		last.WhiteComments = append(last.WhiteComments, text)
+	}
+}
+
+// addVariation attaches a variation to the last move of the line.
+// Variations before the first move have nothing to replace and are dropped.
+func (v *Variation) addVariation(variation *Variation) {
+	if len(v.Moves) == 0 {
+		return
+	}
+
+	last := v.Moves[len(v.Moves)-1]
+	if last.MoveBlack != "" {
+		last.BlackVariations = append(last.BlackVariations, variation)
+	} else {
+		last.WhiteVariations = append(last.WhiteVariations, variation)
+	}
+}
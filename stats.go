@@ -0,0 +1,305 @@
+package pgn
+
+import (
+	"math"
+	"sort"
+	"strings"
+)
+
+// Record counts a player's results.
+type Record struct {
+	Games  int
+	Wins   int
+	Draws  int
+	Losses int
+}
+
+// Points returns the score, a point for a win and half for a draw.
+func (r Record) Points() float64 {
+	return float64(r.Wins) + float64(r.Draws)/2
+}
+
+// Percent returns the score as a share of the games with a known result,
+// from 0 to 100.
+func (r Record) Percent() float64 {
+	decided := r.Wins + r.Draws + r.Losses
+	if decided == 0 {
+		return 0
+	}
+
+	return 100 * r.Points() / float64(decided)
+}
+
+func (r *Record) add(other Record) {
+	r.Games += other.Games
+	r.Wins += other.Wins
+	r.Draws += other.Draws
+	r.Losses += other.Losses
+}
+
+// OpeningCount is how often a player reached an opening.
+type OpeningCount struct {
+	ECO   string
+	Games int
+}
+
+// PlayerStats aggregates a player's games.
+type PlayerStats struct {
+	Name  string
+	White Record
+	Black Record
+	// TimeClasses holds the results of each time control class.
+	TimeClasses map[TimeClass]Record
+
+	plies    int
+	openings map[string]int
+	// rated counts the decided games against rated opponents, for the
+	// performance rating.
+	rated       int
+	opponentElo int
+	ratedPoints float64
+}
+
+func newPlayerStats(name string) *PlayerStats {
+	return &PlayerStats{
+		Name:        name,
+		TimeClasses: map[TimeClass]Record{},
+		openings:    map[string]int{},
+	}
+}
+
+// Total returns the results with both colors.
+func (s *PlayerStats) Total() Record {
+	total := s.White
+	total.add(s.Black)
+	return total
+}
+
+func (s *PlayerStats) Games() int {
+	return s.White.Games + s.Black.Games
+}
+
+// AverageLength returns the average length of the games in moves.
+func (s *PlayerStats) AverageLength() float64 {
+	if s.Games() == 0 {
+		return 0
+	}
+
+	return float64(s.plies) / 2 / float64(s.Games())
+}
+
+// Openings returns the ECO codes of the games, most played first. Games
+// without an ECO tag are classified from their moves.
+func (s *PlayerStats) Openings() []OpeningCount {
+	openings := make([]OpeningCount, 0, len(s.openings))
+	for eco, n := range s.openings {
+		openings = append(openings, OpeningCount{ECO: eco, Games: n})
+	}
+
+	sort.Slice(openings, func(i, j int) bool {
+		if openings[i].Games != openings[j].Games {
+			return openings[i].Games > openings[j].Games
+		}
+		return openings[i].ECO < openings[j].ECO
+	})
+
+	return openings
+}
+
+// PerformanceRating returns the FIDE performance rating of the decided games
+// against opponents with a rating: their average rating plus the difference
+// that the score implies. It is false when there are no such games.
+func (s *PlayerStats) PerformanceRating() (int, bool) {
+	if s.rated == 0 {
+		return 0, false
+	}
+
+	average := float64(s.opponentElo) / float64(s.rated)
+	p := s.ratedPoints / float64(s.rated)
+
+	return int(math.Round(average)) + ratingDifference(p), true
+}
+
+// fideDifferences is the rating difference of FIDE's conversion table for
+// scores from 50% to 100%, by percentage point.
+var fideDifferences = [...]int{
+	0, 7, 14, 21, 29, 36, 43, 50, 57, 65,
+	72, 80, 87, 95, 102, 110, 117, 125, 133, 141,
+	149, 158, 166, 175, 184, 193, 202, 211, 220, 230,
+	240, 251, 262, 273, 284, 296, 309, 322, 336, 351,
+	366, 383, 401, 422, 444, 470, 501, 538, 589, 677,
+	800,
+}
+
+// ratingDifference converts a score fraction to a rating difference.
+func ratingDifference(p float64) int {
+	points := int(math.Round(p*100)) - 50
+	if points < 0 {
+		return -fideDifferences[-points]
+	}
+
+	return fideDifferences[points]
+}
+
+// add counts a game the player played with the color.
+func (s *PlayerStats) add(g *Game, c Color) {
+	result := Record{Games: 1}
+	var points float64
+	switch winner := g.Winner(); {
+	case winner == "Draw":
+		result.Draws, points = 1, 0.5
+	case winner == c.String():
+		result.Wins, points = 1, 1
+	case winner == c.Other().String():
+		result.Losses = 1
+	}
+
+	if c == White {
+		s.White.add(result)
+	} else {
+		s.Black.add(result)
+	}
+
+	class := s.TimeClasses[g.TimeClass()]
+	class.add(result)
+	s.TimeClasses[g.TimeClass()] = class
+
+	s.plies += len(g.Plies())
+
+	eco := g.GetTag("ECO")
+	if eco == "" || eco == "?" {
+		if o, err := g.DetectOpening(); err == nil && o != nil {
+			eco = o.ECO
+		}
+	}
+	if eco != "" && eco != "?" {
+		s.openings[eco]++
+	}
+
+	opponent, rated := g.BlackElo()
+	if c == Black {
+		opponent, rated = g.WhiteElo()
+	}
+	if rated && result.Wins+result.Draws+result.Losses > 0 {
+		s.rated++
+		s.opponentElo += opponent
+		s.ratedPoints += points
+	}
+}
+
+// Players returns the statistics of every player of the collection, most
+// games first. Spellings with the same surname and first initial, as
+// SamePlayer compares them, count as one player under the first spelling
+// seen. A spelling without an initial joins the player with that surname
+// only when there is exactly one; otherwise it stays apart rather than merge
+// different people.
+func (c *Collection) Players() []*PlayerStats {
+	type appearance struct {
+		game  *Game
+		color Color
+	}
+	type player struct {
+		name        string
+		appearances []appearance
+	}
+
+	players := map[[2]string]*player{}
+	order := [][2]string{}
+	initials := map[string]int{}
+
+	count := func(name string, g *Game, color Color) {
+		name = strings.TrimSpace(name)
+		if isUnknownTag(name) {
+			return
+		}
+
+		surname, initial := playerName(name)
+		key := [2]string{surname, initial}
+
+		p, ok := players[key]
+		if !ok {
+			p = &player{name: name}
+			players[key] = p
+			order = append(order, key)
+			if initial != "" {
+				initials[surname]++
+			}
+		}
+		p.appearances = append(p.appearances, appearance{g, color})
+	}
+
+	for _, g := range c.Games {
+		count(g.White(), g, White)
+		count(g.Black(), g, Black)
+	}
+
+	// Fold bare surnames into the only player with that surname.
+	for i, key := range order {
+		surname, initial := key[0], key[1]
+		if initial != "" || initials[surname] != 1 {
+			continue
+		}
+
+		for j, other := range order {
+			if other[0] != surname || other[1] == "" {
+				continue
+			}
+
+			p := players[other]
+			p.appearances = append(p.appearances, players[key].appearances...)
+			if i < j {
+				p.name = players[key].name
+			}
+			delete(players, key)
+			break
+		}
+	}
+
+	stats := []*PlayerStats{}
+	for _, key := range order {
+		p, ok := players[key]
+		if !ok {
+			continue
+		}
+
+		s := newPlayerStats(p.name)
+		for _, a := range p.appearances {
+			s.add(a.game, a.color)
+		}
+		stats = append(stats, s)
+	}
+
+	sort.SliceStable(stats, func(i, j int) bool {
+		if stats[i].Games() != stats[j].Games() {
+			return stats[i].Games() > stats[j].Games()
+		}
+		return stats[i].Name < stats[j].Name
+	})
+
+	return stats
+}
+
+// Player returns the statistics of the games of a player, matching spellings
+// of the name as SamePlayer does. A game in which both players match, as
+// relatives may when the name is a bare surname, counts for the side whose
+// name is spelled exactly as given, and is left out when neither is.
+func (c *Collection) Player(name string) *PlayerStats {
+	s := newPlayerStats(name)
+
+	for _, g := range c.Games {
+		white, black := SamePlayer(name, g.White()), SamePlayer(name, g.Black())
+		if white && black {
+			white = strings.EqualFold(strings.TrimSpace(g.White()), strings.TrimSpace(name))
+			black = !white && strings.EqualFold(strings.TrimSpace(g.Black()), strings.TrimSpace(name))
+		}
+
+		switch {
+		case white:
+			s.add(g, White)
+		case black:
+			s.add(g, Black)
+		}
+	}
+
+	return s
+}
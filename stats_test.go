@@ -0,0 +1,159 @@
+package pgn
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+const statsDatabase = `[White "Carlsen, Magnus"]
+[Black "Nakamura, Hikaru"]
+[WhiteElo "2850"]
+[BlackElo "2780"]
+[TimeControl "180+2"]
+[Result "1-0"]
+
+1. e4 e5 2. Nf3 Nc6 3. Bb5 a6 1-0
+
+[White "Nakamura, Hikaru"]
+[Black "Carlsen, Magnus"]
+[WhiteElo "2780"]
+[BlackElo "2850"]
+[ECO "B90"]
+[TimeControl "5400+30"]
+[Result "1/2-1/2"]
+
+1. e4 c5 1/2-1/2
+
+[White "Magnus Carlsen"]
+[Black "Caruana, Fabiano"]
+[Result "*"]
+
+1. d4 *
+`
+
+func TestPlayer(t *testing.T) {
+	c, err := ReadCollection(strings.NewReader(statsDatabase))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := c.Player("Carlsen")
+	if s.Games() != 3 || s.White != (Record{Games: 2, Wins: 1}) || s.Black != (Record{Games: 1, Draws: 1}) {
+		t.Errorf("records = %+v, %+v", s.White, s.Black)
+	}
+	if total := s.Total(); total.Points() != 1.5 || total.Percent() != 75 {
+		t.Errorf("total = %+v, %.1f%%", total, total.Percent())
+	}
+
+	if rating, ok := s.PerformanceRating(); !ok || rating != 2780+193 {
+		t.Errorf("performance = %d, %v, want %d", rating, ok, 2780+193)
+	}
+	if s.AverageLength() != 1.5 {
+		t.Errorf("average length = %v, want 1.5", s.AverageLength())
+	}
+
+	openings := s.Openings()
+	if len(openings) != 3 || openings[0] != (OpeningCount{ECO: "A40", Games: 1}) || openings[2].ECO != "C70" {
+		t.Errorf("openings = %+v", openings)
+	}
+
+	if s.TimeClasses[Blitz] != (Record{Games: 1, Wins: 1}) || s.TimeClasses[Classical] != (Record{Games: 1, Draws: 1}) {
+		t.Errorf("time classes = %+v", s.TimeClasses)
+	}
+}
+
+func TestPlayerCountsGamesOnce(t *testing.T) {
+	c := NewCollection()
+	for _, pgn := range []string{
+		"[White \"Carlsen, Magnus\"]\n[Black \"Carlsen, Henrik\"]\n[Result \"1-0\"]\n\n1. e4 1-0",
+		"[White \"Carlsen, Henrik\"]\n[Black \"Carlsen, Magnus\"]\n[Result \"0-1\"]\n\n1. e4 0-1",
+		"[White \"Carlsen, Magnus\"]\n[Black \"Nakamura, Hikaru\"]\n[Result \"1/2-1/2\"]\n\n1. e4 1/2-1/2",
+	} {
+		g, err := New(pgn)
+		if err != nil {
+			t.Fatalf("New() error: %v", err)
+		}
+		c.Add(g)
+	}
+
+	if s := c.Player("Carlsen"); s.Games() != 1 || s.Total().Draws != 1 {
+		t.Errorf("Carlsen played %d games, want only the one where a single side matches", s.Games())
+	}
+
+	s := c.Player("Carlsen, Magnus")
+	if s.Games() != 3 || s.White != (Record{Games: 2, Wins: 1, Draws: 1}) || s.Black != (Record{Games: 1, Wins: 1}) {
+		t.Errorf("Magnus Carlsen's records = %+v, %+v", s.White, s.Black)
+	}
+}
+
+func TestPlayers(t *testing.T) {
+	c, err := ReadCollection(strings.NewReader(statsDatabase))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, s := range c.Players() {
+		names = append(names, s.Name)
+	}
+	want := "Carlsen, Magnus|Nakamura, Hikaru|Caruana, Fabiano"
+	if got := strings.Join(names, "|"); got != want {
+		t.Errorf("players = %q, want %q", got, want)
+	}
+
+	if carlsen := c.Players()[0]; carlsen.Games() != 3 || carlsen.White.Games != 2 {
+		t.Errorf("Carlsen's spellings counted %d games, want 3", carlsen.Games())
+	}
+
+	if rating, ok := c.Players()[2].PerformanceRating(); ok {
+		t.Errorf("performance without rated games = %d", rating)
+	}
+}
+
+func TestPlayersSharingSurname(t *testing.T) {
+	input := `[White "Carlsen"]
+[Black "Carlsen, Magnus"]
+[Result "0-1"]
+
+1. e4 e5 0-1
+
+[White "Carlsen, Henrik"]
+[Black "Carlsen, M."]
+[Result "1/2-1/2"]
+
+1. d4 d5 1/2-1/2
+`
+	c, err := ReadCollection(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var rows []string
+	for _, s := range c.Players() {
+		rows = append(rows, fmt.Sprintf("%s:%d", s.Name, s.Games()))
+	}
+	want := "Carlsen, Magnus:2|Carlsen:1|Carlsen, Henrik:1"
+	if got := strings.Join(rows, "|"); got != want {
+		t.Errorf("players = %q, want %q", got, want)
+	}
+}
+
+func TestRatingDifference(t *testing.T) {
+	tests := []struct {
+		p    float64
+		want int
+	}{
+		{0, -800},
+		{0.25, -193},
+		{0.5, 0},
+		{0.666, 125},
+		{1, 800},
+	}
+
+	for _, tt := range tests {
+		if got := ratingDifference(tt.p); got != tt.want {
+			t.Errorf("ratingDifference(%v) = %d, want %d", tt.p, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package pgn
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// SVG draws the position as a self-contained SVG image. There is no last
+// move to shade and no marks to draw, so only the board is shown.
+func (pos *Position) SVG(opts DiagramOptions) string {
+	opts = opts.withDefaults()
+	return pos.diagram(opts, diagramMarks{from: NoSquare, to: NoSquare}).svg(opts.Size)
+}
+
+// SVG draws the position after the given ply as a self-contained SVG image.
+// Ply 0 is the starting position.
+func (g *Game) SVG(ply int, opts DiagramOptions) (string, error) {
+	opts = opts.withDefaults()
+
+	d, err := g.diagram(ply, opts)
+	if err != nil {
+		return "", err
+	}
+
+	return d.svg(opts.Size), nil
+}
+
+func (d *diagram) svg(size int) string {
+	var sb strings.Builder
+
+	board := 8 * squareSize
+	fmt.Fprintf(&sb, `<svg xmlns="http://www.w3.org/2000/svg" version="1.1" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n", size, size, board, board)
+
+	for _, s := range d.shapes {
+		sb.WriteString(`<path d="`)
+		for i, contour := range s.contours {
+			if i > 0 {
+				sb.WriteByte(' ')
+			}
+			sb.WriteString(svgContour(contour))
+		}
+		sb.WriteString(`"`)
+
+		if len(s.contours) > 1 {
+			sb.WriteString(` fill-rule="evenodd"`)
+		}
+		sb.WriteString(svgPaint("fill", s.fill))
+
+		if s.strokeWidth > 0 {
+			sb.WriteString(svgPaint("stroke", s.stroke))
+			fmt.Fprintf(&sb, ` stroke-width="%s" stroke-linejoin="round"`, svgNumber(s.strokeWidth))
+		}
+		sb.WriteString("/>\n")
+	}
+
+	for _, l := range d.labels {
+		fmt.Fprintf(&sb, `<text x="%s" y="%s" font-family="sans-serif" font-size="%s" font-weight="bold"%s>%s</text>`+"\n",
+			svgNumber(l.x), svgNumber(l.y), svgNumber(l.size), svgPaint("fill", l.color), l.text)
+	}
+
+	sb.WriteString("</svg>\n")
+	return sb.String()
+}
+
+func svgContour(points []point) string {
+	parts := make([]string, 0, len(points)+1)
+	for i, p := range points {
+		command := "L"
+		if i == 0 {
+			command = "M"
+		}
+		parts = append(parts, command+svgNumber(p.x)+" "+svgNumber(p.y))
+	}
+
+	return strings.Join(append(parts, "Z"), " ")
+}
+
+// svgPaint returns a fill or stroke attribute, with an opacity attribute for
+// translucent colors.
+func svgPaint(attr string, c color.RGBA) string {
+	s := fmt.Sprintf(` %s="#%02x%02x%02x"`, attr, c.R, c.G, c.B)
+	if c.A != 0xff {
+		s += fmt.Sprintf(` %s-opacity="%s"`, attr, svgNumber(float64(c.A)/0xff))
+	}
+
+	return s
+}
+
+func svgNumber(v float64) string {
+	return strconv.FormatFloat(math.Round(v*100)/100, 'f', -1, 64)
+}
@@ -0,0 +1,77 @@
+package pgn
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestGameSVG(t *testing.T) {
+	game, err := New("[Result \"*\"]\n\n1. e4 {[%cal Gg1f3] [%csl Re5]} e5 *")
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+
+	got, err := game.SVG(1, DefaultDiagramOptions)
+	if err != nil {
+		t.Fatalf("SVG() error: %v", err)
+	}
+
+	decoder := xml.NewDecoder(strings.NewReader(got))
+	for {
+		if _, err := decoder.Token(); err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("SVG() is not well-formed XML: %v", err)
+		}
+	}
+
+	checks := []string{
+		`width="360" height="360" viewBox="0 0 360 360"`,
+		// Last move shading on e2 and e4.
+		`<path d="M180 270 L225 270 L225 315 L180 315 Z" fill="#9bc700" fill-opacity="0.41"/>`,
+		`<path d="M180 180 L225 180 L225 225 L180 225 Z" fill="#9bc700" fill-opacity="0.41"/>`,
+		// Green arrow and red highlight.
+		`fill="#15781b" fill-opacity="0.8"`,
+		`fill-rule="evenodd" fill="#882020"`,
+		`>a</text>`,
+		`>8</text>`,
+	}
+	for _, check := range checks {
+		if !strings.Contains(got, check) {
+			t.Errorf("SVG() does not contain %q", check)
+		}
+	}
+
+	if strings.Contains(got, "href") {
+		t.Errorf("SVG() references external assets")
+	}
+}
+
+func TestPositionSVG(t *testing.T) {
+	pos := StartingPosition()
+
+	plain := pos.SVG(DiagramOptions{Size: 200, Pieces: GeometricPieces})
+	if !strings.Contains(plain, `width="200" height="200"`) {
+		t.Errorf("SVG() ignores Size")
+	}
+	if strings.Contains(plain, "<text") {
+		t.Errorf("SVG() draws coordinates when not asked to")
+	}
+
+	// 64 squares, 30 single-polygon pieces and two kings with a cross.
+	if got := strings.Count(plain, "<path"); got != 98 {
+		t.Errorf("SVG() has %d paths, want 98", got)
+	}
+
+	// From black's side a1 is in the top right corner.
+	flipped := pos.SVG(DiagramOptions{Flip: true, Theme: GreenTheme})
+	if !strings.Contains(flipped, `<path d="M315 0 L360 0 L360 45 L315 45 Z" fill="#769656"/>`) {
+		t.Errorf("SVG() with Flip does not draw a1 in the top right corner")
+	}
+
+	if _, err := (&Game{}).SVG(1, DefaultDiagramOptions); err == nil {
+		t.Errorf("SVG() of a ply out of range did not fail")
+	}
+}
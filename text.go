@@ -0,0 +1,123 @@
+package pgn
+
+import "strings"
+
+// TextOptions configures text board diagrams.
+type TextOptions struct {
+	// Unicode draws pieces as figurines instead of FEN letters.
+	Unicode bool
+	// Flip draws the board from black's side.
+	Flip bool
+	// Coordinates labels the files and ranks.
+	Coordinates bool
+}
+
+var figurines = map[Piece]string{
+	{Type: King, Color: White}:   "♔",
+	{Type: Queen, Color: White}:  "♕",
+	{Type: Rook, Color: White}:   "♖",
+	{Type: Bishop, Color: White}: "♗",
+	{Type: Knight, Color: White}: "♘",
+	{Type: Pawn, Color: White}:   "♙",
+	{Type: King, Color: Black}:   "♚",
+	{Type: Queen, Color: Black}:  "♛",
+	{Type: Rook, Color: Black}:   "♜",
+	{Type: Bishop, Color: Black}: "♝",
+	{Type: Knight, Color: Black}: "♞",
+	{Type: Pawn, Color: Black}:   "♟",
+}
+
+// Text draws the position as a text diagram followed by the side to move.
+func (pos *Position) Text(opts TextOptions) string {
+	return pos.text(opts, NoSquare, NoSquare)
+}
+
+// BoardText draws the position after the given ply as a text diagram. The
+// squares of the move that reached it are shown in brackets.
+func (g *Game) BoardText(ply int, opts TextOptions) (string, error) {
+	pos, from, to, err := g.renderState(ply)
+	if err != nil {
+		return "", err
+	}
+
+	return pos.text(opts, from, to), nil
+}
+
+func (pos *Position) text(opts TextOptions, from, to Square) string {
+	var sb strings.Builder
+
+	margin := ""
+	if opts.Coordinates {
+		margin = "  "
+	}
+
+	border := margin + "+" + strings.Repeat("-", 24) + "+\n"
+	sb.WriteString(border)
+
+	for row := 0; row < 8; row++ {
+		rank := 7 - row
+		if opts.Flip {
+			rank = row
+		}
+
+		if opts.Coordinates {
+			sb.WriteString(string(rune('1'+rank)) + " ")
+		}
+		sb.WriteString("|")
+
+		for col := 0; col < 8; col++ {
+			file := col
+			if opts.Flip {
+				file = 7 - col
+			}
+
+			sq := newSquare(file, rank)
+			symbol := pos.squareSymbol(sq, opts.Unicode)
+
+			if sq == from || sq == to {
+				sb.WriteString("[" + symbol + "]")
+			} else {
+				sb.WriteString(" " + symbol + " ")
+			}
+		}
+
+		sb.WriteString("|\n")
+	}
+
+	sb.WriteString(border)
+
+	if opts.Coordinates {
+		files := margin + " "
+		for col := 0; col < 8; col++ {
+			file := col
+			if opts.Flip {
+				file = 7 - col
+			}
+			files += " " + string(rune('a'+file)) + " "
+		}
+		sb.WriteString(strings.TrimRight(files, " ") + "\n")
+	}
+
+	sb.WriteString(pos.turn.String() + " to move")
+	if pos.InCheck() {
+		sb.WriteString(", in check")
+	}
+	sb.WriteString("\n")
+
+	return sb.String()
+}
+
+func (pos *Position) squareSymbol(sq Square, unicode bool) string {
+	p := pos.board[sq]
+
+	switch {
+	case p.IsEmpty() && unicode:
+		return "·"
+	case p.IsEmpty():
+		return "."
+	case unicode:
+		return figurines[p]
+	}
+
+	return p.String()
+}
@@ -0,0 +1,89 @@
+package pgn
+
+import "testing"
+
+func TestBoardText(t *testing.T) {
+	game, err := New("[Result \"*\"]\n\n1. e4 e5 2. Nf3 Nc6 3. Bc4 Nf6 4. O-O *")
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+
+	got, err := game.BoardText(7, TextOptions{Coordinates: true})
+	if err != nil {
+		t.Fatalf("BoardText() error: %v", err)
+	}
+
+	expected := `  +------------------------+
+8 | r  .  b  q  k  b  .  r |
+7 | p  p  p  p  .  p  p  p |
+6 | .  .  n  .  .  n  .  . |
+5 | .  .  .  .  p  .  .  . |
+4 | .  .  B  .  P  .  .  . |
+3 | .  .  .  .  .  N  .  . |
+2 | P  P  P  P  .  P  P  P |
+1 | R  N  B  Q [.] R [K] . |
+  +------------------------+
+    a  b  c  d  e  f  g  h
+Black to move
+`
+	if got != expected {
+		t.Errorf("BoardText() =\n%s\nwant\n%s", got, expected)
+	}
+
+	got, err = game.BoardText(1, TextOptions{Unicode: true, Flip: true})
+	if err != nil {
+		t.Fatalf("BoardText() error: %v", err)
+	}
+
+	expected = `+------------------------+
+| ♖  ♘  ♗  ♔  ♕  ♗  ♘  ♖ |
+| ♙  ♙  ♙ [·] ♙  ♙  ♙  ♙ |
+| ·  ·  ·  ·  ·  ·  ·  · |
+| ·  ·  · [♙] ·  ·  ·  · |
+| ·  ·  ·  ·  ·  ·  ·  · |
+| ·  ·  ·  ·  ·  ·  ·  · |
+| ♟  ♟  ♟  ♟  ♟  ♟  ♟  ♟ |
+| ♜  ♞  ♝  ♚  ♛  ♝  ♞  ♜ |
++------------------------+
+Black to move
+`
+	if got != expected {
+		t.Errorf("BoardText() flipped =\n%s\nwant\n%s", got, expected)
+	}
+
+	if _, err := game.BoardText(8, TextOptions{}); err == nil {
+		t.Errorf("BoardText() past the last ply expected error")
+	}
+}
+
+func TestPositionText(t *testing.T) {
+	pos, err := ParseFEN("4k3/8/8/8/8/8/8/4K2R b - - 0 1")
+	if err != nil {
+		t.Fatalf("ParseFEN() error: %v", err)
+	}
+
+	pos, err = pos.PlaySAN("Kd7")
+	if err != nil {
+		t.Fatalf("PlaySAN() error: %v", err)
+	}
+	pos, err = pos.PlaySAN("Rh7+")
+	if err != nil {
+		t.Fatalf("PlaySAN() error: %v", err)
+	}
+
+	expected := `+------------------------+
+| .  .  .  .  .  .  .  . |
+| .  .  .  k  .  .  .  R |
+| .  .  .  .  .  .  .  . |
+| .  .  .  .  .  .  .  . |
+| .  .  .  .  .  .  .  . |
+| .  .  .  .  .  .  .  . |
+| .  .  .  .  .  .  .  . |
+| .  .  .  .  K  .  .  . |
++------------------------+
+Black to move, in check
+`
+	if got := pos.Text(TextOptions{}); got != expected {
+		t.Errorf("Text() =\n%s\nwant\n%s", got, expected)
+	}
+}
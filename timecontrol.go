@@ -0,0 +1,306 @@
+package pgn
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TimePeriod is one period of a time control, such as 40 moves in two hours
+// or the rest of the game in five minutes plus two seconds a move.
+type TimePeriod struct {
+	// Moves is the number of moves the period lasts, or zero for the rest
+	// of the game.
+	Moves     int
+	Time      time.Duration
+	Increment time.Duration
+	// Sandclock periods pass the time a player uses to the opponent.
+	Sandclock bool
+}
+
+func (tp TimePeriod) String() string {
+	var s string
+	if tp.Sandclock {
+		s = "*"
+	}
+	if tp.Moves > 0 {
+		s += strconv.Itoa(tp.Moves) + "/"
+	}
+
+	s += formatSeconds(tp.Time)
+	if tp.Increment > 0 {
+		s += "+" + formatSeconds(tp.Increment)
+	}
+
+	return s
+}
+
+// TimeControl is the value of a TimeControl tag. A game either has an
+// unknown time control ("?"), no time control ("-") or a sequence of
+// periods.
+type TimeControl struct {
+	Unknown   bool
+	Unlimited bool
+	Periods   []TimePeriod
+}
+
+func (tc TimeControl) String() string {
+	if tc.Unknown {
+		return "?"
+	}
+
+	if tc.Unlimited {
+		return "-"
+	}
+
+	fields := make([]string, len(tc.Periods))
+	for i, p := range tc.Periods {
+		fields[i] = p.String()
+	}
+
+	return strings.Join(fields, ":")
+}
+
+// ParseTimeControl parses a TimeControl tag value as described in the PGN
+// specification, such as "40/7200:3600", "300+2", "*180", "-" or "?".
+func ParseTimeControl(s string) (TimeControl, error) {
+	s = strings.TrimSpace(s)
+
+	switch s {
+	case "?", "":
+		return TimeControl{Unknown: true}, nil
+	case "-":
+		return TimeControl{Unlimited: true}, nil
+	}
+
+	tc := TimeControl{}
+	for _, field := range strings.Split(s, ":") {
+		p, err := parseTimePeriod(field)
+		if err != nil {
+			return TimeControl{}, fmt.Errorf("invalid time control %q: %v", s, err)
+		}
+
+		tc.Periods = append(tc.Periods, p)
+	}
+
+	return tc, nil
+}
+
+func parseTimePeriod(field string) (TimePeriod, error) {
+	var p TimePeriod
+
+	if strings.HasPrefix(field, "*") {
+		p.Sandclock = true
+		field = field[1:]
+	}
+
+	if moves, rest, ok := strings.Cut(field, "/"); ok {
+		n, err := strconv.Atoi(moves)
+		if err != nil || n <= 0 {
+			return p, fmt.Errorf("invalid move count %q", moves)
+		}
+		p.Moves = n
+		field = rest
+	}
+
+	base, increment, hasIncrement := strings.Cut(field, "+")
+
+	d, err := parseSeconds(base)
+	if err != nil {
+		return p, err
+	}
+	p.Time = d
+
+	if hasIncrement {
+		if p.Increment, err = parseSeconds(increment); err != nil {
+			return p, err
+		}
+	}
+
+	return p, nil
+}
+
+func parseSeconds(s string) (time.Duration, error) {
+	seconds, err := strconv.ParseFloat(s, 64)
+	if err != nil || seconds < 0 {
+		return 0, fmt.Errorf("invalid number of seconds %q", s)
+	}
+
+	return time.Duration(seconds * float64(time.Second)), nil
+}
+
+func formatSeconds(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', -1, 64)
+}
+
+// TimeControl returns the parsed TimeControl tag of the game.
+func (g *Game) TimeControl() (TimeControl, error) {
+	return ParseTimeControl(g.GetTag("TimeControl"))
+}
+
+// periodAt returns the period a player's nth move, counted from 1, is
+// played in, and whether the move completes it. A last period with a move
+// count, as in "40/7200", repeats for the rest of the game.
+func (tc TimeControl) periodAt(n int) (TimePeriod, bool) {
+	for i, p := range tc.Periods {
+		if p.Moves == 0 {
+			return p, false
+		}
+
+		if i == len(tc.Periods)-1 {
+			n = (n-1)%p.Moves + 1
+		}
+
+		if n <= p.Moves {
+			return p, n == p.Moves
+		}
+		n -= p.Moves
+	}
+
+	return TimePeriod{}, false
+}
+
+// TimeClass is the speed category of a game.
+type TimeClass int
+
+const (
+	UnknownTimeClass TimeClass = iota
+	Bullet
+	Blitz
+	Rapid
+	Classical
+	Untimed
+)
+
+func (tc TimeClass) String() string {
+	switch tc {
+	case Bullet:
+		return "bullet"
+	case Blitz:
+		return "blitz"
+	case Rapid:
+		return "rapid"
+	case Classical:
+		return "classical"
+	case Untimed:
+		return "untimed"
+	}
+
+	return "unknown"
+}
+
+// EstimatedDuration returns the time each player has for a game of 40
+// moves, the measure lichess uses to classify time controls.
+func (tc TimeControl) EstimatedDuration() time.Duration {
+	if len(tc.Periods) == 0 {
+		return 0
+	}
+
+	p := tc.Periods[0]
+	if p.Moves > 0 {
+		return p.Time*40/time.Duration(p.Moves) + 40*p.Increment
+	}
+
+	return p.Time + 40*p.Increment
+}
+
+// Class classifies the time control with the lichess thresholds on the
+// estimated duration: under 3 minutes is bullet, under 8 blitz, under 25
+// rapid and anything longer classical.
+func (tc TimeControl) Class() TimeClass {
+	switch {
+	case tc.Unlimited:
+		return Untimed
+	case len(tc.Periods) == 0:
+		return UnknownTimeClass
+	}
+
+	switch d := tc.EstimatedDuration(); {
+	case d < 3*time.Minute:
+		return Bullet
+	case d < 8*time.Minute:
+		return Blitz
+	case d < 25*time.Minute:
+		return Rapid
+	default:
+		return Classical
+	}
+}
+
+// TimeClass classifies the game by its TimeControl tag.
+func (g *Game) TimeClass() TimeClass {
+	tc, err := g.TimeControl()
+	if err != nil {
+		return UnknownTimeClass
+	}
+
+	return tc.Class()
+}
+
+// ClockState is the time left on both clocks after a ply.
+type ClockState struct {
+	Ply int
+	// Remaining and Known are indexed by Color. A clock is unknown after a
+	// move with neither %clk nor %emt, until the next %clk.
+	Remaining [2]time.Duration
+	Known     [2]bool
+}
+
+// ReconstructClocks replays the time control of the game and returns the
+// clocks before the first move and after every ply. A %clk annotation gives
+// the mover's clock directly. Otherwise the clock is worked out from the
+// %emt annotation, the increment and the time added when a period ends.
+func (g *Game) ReconstructClocks() ([]ClockState, error) {
+	tc, err := g.TimeControl()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(tc.Periods) == 0 {
+		return nil, fmt.Errorf("time control %q has no periods", tc)
+	}
+
+	state := ClockState{
+		Remaining: [2]time.Duration{tc.Periods[0].Time, tc.Periods[0].Time},
+		Known:     [2]bool{true, true},
+	}
+	states := []ClockState{state}
+
+	var moves [2]int
+	for _, p := range g.Plies() {
+		c := p.Color
+		moves[c]++
+		period, completes := tc.periodAt(moves[c])
+
+		state.Ply = p.Index
+
+		if clock, ok := p.Clock(); ok {
+			state.Remaining[c], state.Known[c] = clock, true
+		} else if elapsed, ok := p.ElapsedTime(); ok && state.Known[c] {
+			state.Remaining[c] += period.Increment - elapsed
+			if completes {
+				next, _ := tc.periodAt(moves[c] + 1)
+				state.Remaining[c] += next.Time
+			}
+		} else {
+			state.Known[c] = false
+		}
+
+		if period.Sandclock {
+			if elapsed, ok := p.ElapsedTime(); ok {
+				state.Remaining[c.Other()] += elapsed
+			} else {
+				state.Known[c.Other()] = false
+			}
+		}
+
+		if state.Remaining[c] < 0 {
+			state.Remaining[c] = 0
+		}
+
+		states = append(states, state)
+	}
+
+	return states, nil
+}
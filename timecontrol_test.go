@@ -0,0 +1,114 @@
+package pgn
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseTimeControl(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected TimeControl
+		class    TimeClass
+	}{
+		{"?", TimeControl{Unknown: true}, UnknownTimeClass},
+		{"-", TimeControl{Unlimited: true}, Untimed},
+		{"300+2", TimeControl{Periods: []TimePeriod{{Time: 5 * time.Minute, Increment: 2 * time.Second}}}, Blitz},
+		{"60", TimeControl{Periods: []TimePeriod{{Time: time.Minute}}}, Bullet},
+		{"*180", TimeControl{Periods: []TimePeriod{{Time: 3 * time.Minute, Sandclock: true}}}, Blitz},
+		{"900+10", TimeControl{Periods: []TimePeriod{{Time: 15 * time.Minute, Increment: 10 * time.Second}}}, Rapid},
+		{"40/7200:3600", TimeControl{Periods: []TimePeriod{{Moves: 40, Time: 2 * time.Hour}, {Time: time.Hour}}}, Classical},
+	}
+
+	for _, tt := range tests {
+		tc, err := ParseTimeControl(tt.input)
+		if err != nil {
+			t.Errorf("ParseTimeControl(%q) error: %v", tt.input, err)
+			continue
+		}
+
+		if !reflect.DeepEqual(tc, tt.expected) {
+			t.Errorf("ParseTimeControl(%q) = %+v, want %+v", tt.input, tc, tt.expected)
+		}
+		if got := tc.String(); got != tt.input {
+			t.Errorf("TimeControl.String() = %q, want %q", got, tt.input)
+		}
+		if got := tc.Class(); got != tt.class {
+			t.Errorf("ParseTimeControl(%q).Class() = %v, want %v", tt.input, got, tt.class)
+		}
+	}
+
+	for _, input := range []string{"abc", "40/", "0/60", "300+x", "300:"} {
+		if _, err := ParseTimeControl(input); err == nil {
+			t.Errorf("ParseTimeControl(%q) expected error", input)
+		}
+	}
+}
+
+func TestReconstructClocksFromElapsed(t *testing.T) {
+	input := `[TimeControl "2/60:30+5"]
+[Result "*"]
+
+1. e4 {[%emt 0:00:10]} e5 {[%emt 0:00:20]} 2. Nf3 {[%emt 0:00:05]} Nc6 3. Bb5 {[%emt 0:00:15]} a6 {[%clk 0:00:40]} *`
+
+	game, err := New(input)
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+
+	states, err := game.ReconstructClocks()
+	if err != nil {
+		t.Fatalf("ReconstructClocks() error: %v", err)
+	}
+
+	expected := []struct {
+		white, black time.Duration
+		blackKnown   bool
+	}{
+		{60 * time.Second, 60 * time.Second, true},
+		{50 * time.Second, 60 * time.Second, true},
+		{50 * time.Second, 40 * time.Second, true},
+		{75 * time.Second, 40 * time.Second, true},
+		{75 * time.Second, 40 * time.Second, false},
+		{65 * time.Second, 40 * time.Second, false},
+		{65 * time.Second, 40 * time.Second, true},
+	}
+
+	if len(states) != len(expected) {
+		t.Fatalf("len(ReconstructClocks()) = %d, want %d", len(states), len(expected))
+	}
+
+	for i, want := range expected {
+		got := states[i]
+		if got.Ply != i || got.Remaining[White] != want.white || !got.Known[White] {
+			t.Errorf("ply %d white clock = %v (known %v), want %v", i, got.Remaining[White], got.Known[White], want.white)
+		}
+		if got.Known[Black] != want.blackKnown || (want.blackKnown && got.Remaining[Black] != want.black) {
+			t.Errorf("ply %d black clock = %v (known %v), want %v (known %v)", i, got.Remaining[Black], got.Known[Black], want.black, want.blackKnown)
+		}
+	}
+}
+
+func TestTimeUsageWithIncrement(t *testing.T) {
+	input := `[TimeControl "180+2"]
+[Result "*"]
+
+1. e4 {[%clk 0:03:00]} e5 {[%clk 0:03:00]} 2. Nf3 {[%clk 0:02:55]} Nc6 {[%clk 0:03:01]} *`
+
+	game, err := New(input)
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+
+	if got := game.TimeClass(); got != Blitz {
+		t.Errorf("TimeClass() = %v, want %v", got, Blitz)
+	}
+
+	if u := game.TimeUsage(White)[1]; u.Elapsed != 7*time.Second {
+		t.Errorf("white elapsed = %v, want 7s", u.Elapsed)
+	}
+	if u := game.TimeUsage(Black)[1]; u.Elapsed != time.Second {
+		t.Errorf("black elapsed = %v, want 1s", u.Elapsed)
+	}
+}
@@ -5,12 +5,18 @@ type tokenType string
 type token struct {
 	Type    tokenType
 	Literal string
+	Line    int
+	Column  int
 }
 
 func (t token) TokenLiteral() string {
 	return t.Literal
 }
 
+func (t token) position() textPosition {
+	return textPosition{line: t.Line, column: t.Column}
+}
+
 const (
 	PERIOD     = "."
 	ASTERIX    = "*"
@@ -28,8 +34,9 @@ const (
 	LANGLE = "<"
 	RANGLE = ">"
 
-	NAG    = "NAG"
-	SYMBOL = "SYMBOL"
+	NAG     = "NAG"
+	SYMBOL  = "SYMBOL"
+	COMMENT = "COMMENT"
 
 	EOF = "EOF"
 )
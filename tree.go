@@ -0,0 +1,208 @@
+package pgn
+
+import "sort"
+
+// MoveStats summarizes the games in which a move was played from a position.
+type MoveStats struct {
+	SAN       string
+	Games     int
+	WhiteWins int
+	Draws     int
+	BlackWins int
+	// LastPlayed is the latest date of the games, zero when none is known.
+	LastPlayed Date
+
+	eloSum   int
+	eloGames int
+}
+
+// decided is the number of games with a known result.
+func (s MoveStats) decided() int {
+	return s.WhiteWins + s.Draws + s.BlackWins
+}
+
+func (s MoveStats) percent(n int) float64 {
+	if s.decided() == 0 {
+		return 0
+	}
+
+	return 100 * float64(n) / float64(s.decided())
+}
+
+// WhitePercent returns the share of White wins among the games with a known
+// result, from 0 to 100.
+func (s MoveStats) WhitePercent() float64 {
+	return s.percent(s.WhiteWins)
+}
+
+// DrawPercent returns the share of draws among the games with a known
+// result, from 0 to 100.
+func (s MoveStats) DrawPercent() float64 {
+	return s.percent(s.Draws)
+}
+
+// BlackPercent returns the share of Black wins among the games with a known
+// result, from 0 to 100.
+func (s MoveStats) BlackPercent() float64 {
+	return s.percent(s.BlackWins)
+}
+
+// AverageElo returns the average rating of the players who made the move,
+// over the games where it is known. It is false when no rating is known.
+func (s MoveStats) AverageElo() (int, bool) {
+	if s.eloGames == 0 {
+		return 0, false
+	}
+
+	return (s.eloSum + s.eloGames/2) / s.eloGames, true
+}
+
+func (s *MoveStats) add(other MoveStats) {
+	s.Games += other.Games
+	s.WhiteWins += other.WhiteWins
+	s.Draws += other.Draws
+	s.BlackWins += other.BlackWins
+	s.eloSum += other.eloSum
+	s.eloGames += other.eloGames
+	if other.LastPlayed.Compare(s.LastPlayed) > 0 {
+		s.LastPlayed = other.LastPlayed
+	}
+}
+
+// OpeningTree collects, for every position reached in a set of games, the
+// moves played from it. Positions are keyed by their hash, so transpositions
+// share their statistics.
+type OpeningTree struct {
+	depth int
+	moves map[uint64]map[string]*MoveStats
+}
+
+// NewOpeningTree creates a tree of the first depth plies of each game, or of
+// whole games when depth is zero.
+func NewOpeningTree(depth int) *OpeningTree {
+	return &OpeningTree{
+		depth: depth,
+		moves: map[uint64]map[string]*MoveStats{},
+	}
+}
+
+// Add replays the game and counts its moves. A game that returns to a
+// position is counted there once, for the first move played from it.
+func (t *OpeningTree) Add(g *Game) error {
+	positions, err := g.Positions()
+	if err != nil {
+		return err
+	}
+
+	game := MoveStats{Games: 1}
+	switch g.Result() {
+	case "1-0":
+		game.WhiteWins = 1
+	case "1/2-1/2":
+		game.Draws = 1
+	case "0-1":
+		game.BlackWins = 1
+	}
+	if date, err := g.ParsedDate(); err == nil {
+		game.LastPlayed = date
+	}
+	whiteElo, whiteRated := g.WhiteElo()
+	blackElo, blackRated := g.BlackElo()
+
+	seen := map[uint64]bool{}
+	for ply, p := range g.Plies() {
+		if t.depth > 0 && ply >= t.depth {
+			break
+		}
+
+		h := positions[ply].Hash()
+		if seen[h] {
+			continue
+		}
+		seen[h] = true
+
+		stats := game
+		elo, rated := whiteElo, whiteRated
+		if positions[ply].Turn() == Black {
+			elo, rated = blackElo, blackRated
+		}
+		if rated {
+			stats.eloSum, stats.eloGames = elo, 1
+		}
+
+		// Key by the SAN the position writes, so that spellings such as
+		// "Ngf3" and "Nf3" or "0-0" and "O-O" count as one move.
+		m, err := positions[ply].parseSAN(p.SAN)
+		if err != nil {
+			return err
+		}
+		t.move(h, stripSANSuffix(positions[ply].san(m))).add(stats)
+	}
+
+	return nil
+}
+
+func (t *OpeningTree) move(h uint64, san string) *MoveStats {
+	moves, ok := t.moves[h]
+	if !ok {
+		moves = map[string]*MoveStats{}
+		t.moves[h] = moves
+	}
+
+	stats, ok := moves[san]
+	if !ok {
+		stats = &MoveStats{SAN: san}
+		moves[san] = stats
+	}
+
+	return stats
+}
+
+// MovesHash returns the moves played from the position with hash h, most
+// played first.
+func (t *OpeningTree) MovesHash(h uint64) []MoveStats {
+	moves := make([]MoveStats, 0, len(t.moves[h]))
+	for _, stats := range t.moves[h] {
+		moves = append(moves, *stats)
+	}
+
+	sort.Slice(moves, func(i, j int) bool {
+		if moves[i].Games != moves[j].Games {
+			return moves[i].Games > moves[j].Games
+		}
+		return moves[i].SAN < moves[j].SAN
+	})
+
+	return moves
+}
+
+// Moves returns the moves played from pos, most played first.
+func (t *OpeningTree) Moves(pos *Position) []MoveStats {
+	return t.MovesHash(pos.Hash())
+}
+
+// MovesFEN returns the moves played from the position described by fen.
+func (t *OpeningTree) MovesFEN(fen string) ([]MoveStats, error) {
+	h, err := HashFEN(fen)
+	if err != nil {
+		return nil, err
+	}
+
+	return t.MovesHash(h), nil
+}
+
+// Total sums the statistics of the moves played from pos, giving those of
+// the games that continued from it. The SAN of the total is empty.
+func (t *OpeningTree) Total(pos *Position) MoveStats {
+	total := MoveStats{}
+	for _, stats := range t.moves[pos.Hash()] {
+		total.add(*stats)
+	}
+
+	return total
+}
+
+// Len returns the number of positions with moves in the tree.
+func (t *OpeningTree) Len() int {
+	return len(t.moves)
+}
@@ -0,0 +1,152 @@
+package pgn
+
+import (
+	"strings"
+	"testing"
+)
+
+const treeDatabase = `[Date "2023.05.10"]
+[WhiteElo "2800"]
+[BlackElo "2700"]
+[Result "1-0"]
+
+1. e4 c5 2. Nf3 d6 1-0
+
+[Date "2024.01.02"]
+[WhiteElo "2600"]
+[Result "1/2-1/2"]
+
+1. Nf3 c5 2. e4 Nc6 1/2-1/2
+
+[Date "2022.??.??"]
+[Result "0-1"]
+
+1. e4 e5 0-1
+
+[Result "*"]
+
+1. Nf3 Nf6 2. Ng1 Ng8 3. Nf3 *
+`
+
+func readTestTree(t *testing.T, depth int) *OpeningTree {
+	t.Helper()
+
+	c, err := ReadCollection(strings.NewReader(treeDatabase))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tree := NewOpeningTree(depth)
+	for _, g := range c.Games {
+		if err := tree.Add(g); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return tree
+}
+
+func TestOpeningTree(t *testing.T) {
+	tree := readTestTree(t, 0)
+
+	moves, err := tree.MovesFEN(StartingFEN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(moves) != 2 || moves[0].SAN != "Nf3" || moves[1].SAN != "e4" {
+		t.Fatalf("moves from the start = %+v", moves)
+	}
+
+	e4 := moves[1]
+	if e4.Games != 2 || e4.WhitePercent() != 50 || e4.BlackPercent() != 50 || e4.LastPlayed.String() != "2023.05.10" {
+		t.Errorf("e4 = %+v", e4)
+	}
+	if elo, ok := e4.AverageElo(); !ok || elo != 2800 {
+		t.Errorf("e4 average Elo = %d, %v, want 2800", elo, ok)
+	}
+
+	// The unfinished game counts as played but not in the percentages, and
+	// is counted once though it returns to the starting position.
+	nf3 := moves[0]
+	if nf3.Games != 2 || nf3.DrawPercent() != 100 || nf3.LastPlayed.String() != "2024.01.02" {
+		t.Errorf("Nf3 = %+v", nf3)
+	}
+
+	total := tree.Total(StartingPosition())
+	if total.Games != 4 || total.WhiteWins != 1 || total.Draws != 1 || total.BlackWins != 1 {
+		t.Errorf("total = %+v", total)
+	}
+}
+
+func TestOpeningTreeTranspositions(t *testing.T) {
+	tree := readTestTree(t, 0)
+
+	start := StartingPosition()
+	pos, err := start.PlayLine("1. e4 c5 2.Nf3")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	moves := tree.Moves(pos)
+	if len(moves) != 2 || moves[0].SAN != "Nc6" || moves[1].SAN != "d6" {
+		t.Fatalf("moves after the Sicilian = %+v", moves)
+	}
+	if elo, ok := moves[0].AverageElo(); ok {
+		t.Errorf("Nc6 average Elo = %d, want none", elo)
+	}
+	if elo, _ := moves[1].AverageElo(); elo != 2700 {
+		t.Errorf("d6 average Elo = %d, want 2700", elo)
+	}
+
+	if _, err := start.PlayLine("1. e4 e4"); err == nil {
+		t.Error("PlayLine accepted an illegal move")
+	}
+}
+
+func TestOpeningTreeDepth(t *testing.T) {
+	tree := readTestTree(t, 2)
+
+	pos, err := StartingPosition().PlayLine("1. e4 c5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if moves := tree.Moves(pos); len(moves) != 0 {
+		t.Errorf("moves beyond the depth = %+v", moves)
+	}
+}
+
+func TestOpeningTreeSpellings(t *testing.T) {
+	games, err := NewGames(`[Result "1-0"]
+
+1. e4 e5 2. Ngf3 Nc6 3. Bc4 Bc5 4. 0-0 1-0
+
+[Result "0-1"]
+
+1. e4 e5 2. Nf3 Nc6 3. Bc4 Bc5 4. O-O+ 0-1`)
+	if err != nil {
+		t.Fatalf("NewGames() error: %v", err)
+	}
+
+	tree := NewOpeningTree(0)
+	for _, g := range games {
+		if err := tree.Add(g); err != nil {
+			t.Fatalf("Add() error: %v", err)
+		}
+	}
+
+	pos, err := StartingPosition().PlayLine("1. e4 e5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if moves := tree.Moves(pos); len(moves) != 1 || moves[0].SAN != "Nf3" || moves[0].Games != 2 {
+		t.Errorf("moves after 1. e4 e5 = %+v, want Nf3 twice", moves)
+	}
+
+	if pos, err = pos.PlayLine("2. Nf3 Nc6 3. Bc4 Bc5"); err != nil {
+		t.Fatal(err)
+	}
+	castles := tree.Moves(pos)
+	if len(castles) != 1 || castles[0].SAN != "O-O" || castles[0].WhiteWins != 1 || castles[0].BlackWins != 1 {
+		t.Errorf("moves before castling = %+v, want O-O twice", castles)
+	}
+}
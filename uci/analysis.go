@@ -0,0 +1,208 @@
+package uci
+
+import (
+	"fmt"
+
+	"github.com/Shobhit-Nagpal/pgn"
+)
+
+// Options configures AnalyzeGame.
+type Options struct {
+	Limit Limit
+	// MistakeThreshold and BlunderThreshold are the centipawns a move must
+	// lose to be marked "?" ($2) or "??" ($4).
+	MistakeThreshold int
+	BlunderThreshold int
+	// VariationLength is the number of plies of the engine's best line added
+	// as a variation to mistakes and blunders. Zero adds no variations.
+	VariationLength int
+	// MaxScore caps evaluations, in centipawns, before losses are computed,
+	// so that missing a faster mate in a won position is not a blunder.
+	MaxScore int
+}
+
+// DefaultOptions searches to depth 18 and marks moves losing a pawn as
+// mistakes and moves losing three pawns as blunders.
+var DefaultOptions = Options{
+	Limit:            Limit{Depth: 18},
+	MistakeThreshold: 100,
+	BlunderThreshold: 300,
+	VariationLength:  8,
+	MaxScore:         1000,
+}
+
+// PlyAnalysis is the engine's verdict on one ply of a game.
+type PlyAnalysis struct {
+	Ply int
+	SAN string
+	// Eval is the evaluation after the ply. It is nil when the game is over
+	// after the ply.
+	Eval *pgn.Evaluation
+	// Best is the engine's analysis of the position before the ply.
+	Best *Analysis
+	// Loss is the centipawns the move lost against the engine's best move,
+	// from the mover's point of view.
+	Loss int
+	// NAG is "2" for a mistake, "4" for a blunder and "" otherwise.
+	NAG string
+}
+
+// AnalyzeGame analyzes every position of the game's main line and annotates
+// the game: each ply gets an %eval comment, and mistakes and blunders get a
+// "?" or "??" NAG and a variation with the engine's best line. The game is
+// only annotated once every position has been analyzed.
+func AnalyzeGame(e *Engine, g *pgn.Game, opts Options) ([]PlyAnalysis, error) {
+	positions, err := g.Positions()
+	if err != nil {
+		return nil, err
+	}
+
+	start := positions[0]
+	if v := start.Variant(); v != pgn.Standard && v != pgn.Chess960 {
+		return nil, fmt.Errorf("UCI analysis does not support the %s variant", v.Name())
+	}
+
+	if start.IsChess960() {
+		if err := e.SetOption("UCI_Chess960", "true"); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := e.NewGame(); err != nil {
+		return nil, err
+	}
+
+	plies := g.Plies()
+	moves := make([]string, len(plies))
+	for i, p := range plies {
+		if moves[i], err = positions[i].SANToUCI(p.SAN); err != nil {
+			return nil, fmt.Errorf("ply %d: %v", i+1, err)
+		}
+	}
+
+	analyses := make([]*Analysis, len(positions))
+	for i, pos := range positions {
+		if pos.Outcome() != "" {
+			continue
+		}
+
+		if analyses[i], err = e.Analyze(start, moves[:i], opts.Limit); err != nil {
+			return nil, fmt.Errorf("ply %d: %v", i, err)
+		}
+	}
+
+	// Judge every ply before touching the game, so that an error cannot
+	// leave it half annotated.
+	results := make([]PlyAnalysis, len(plies))
+	lines := make([][]string, len(plies))
+	for i, p := range plies {
+		ply := i + 1
+		result := PlyAnalysis{Ply: ply, SAN: p.SAN, Best: analyses[i]}
+
+		after := score(positions[ply], analyses[ply], opts.MaxScore)
+		if analyses[ply] != nil {
+			eval := analyses[ply].Eval
+			result.Eval = &eval
+		}
+
+		if analyses[i] != nil {
+			before := score(positions[i], analyses[i], opts.MaxScore)
+			result.Loss = before - after
+			if p.Color == pgn.Black {
+				result.Loss = -result.Loss
+			}
+			// The engine's own choice loses nothing, whatever the noise
+			// between the two searches says.
+			if result.Loss < 0 || moves[i] == analyses[i].BestMove {
+				result.Loss = 0
+			}
+		}
+
+		switch {
+		case opts.BlunderThreshold > 0 && result.Loss >= opts.BlunderThreshold:
+			result.NAG = "4"
+		case opts.MistakeThreshold > 0 && result.Loss >= opts.MistakeThreshold:
+			result.NAG = "2"
+		}
+
+		if result.NAG != "" {
+			lines[i] = bestLine(positions[i], analyses[i], moves[i], opts.VariationLength)
+		}
+
+		results[i] = result
+	}
+
+	for i, result := range results {
+		if result.Eval != nil {
+			if err := g.SetEvaluation(result.Ply, *result.Eval); err != nil {
+				return nil, err
+			}
+		}
+
+		if result.NAG != "" {
+			if err := g.AddAnnotation(result.Ply, result.NAG); err != nil {
+				return nil, err
+			}
+		}
+
+		if len(lines[i]) > 0 {
+			if _, err := g.AddVariation(result.Ply, lines[i]); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return results, nil
+}
+
+// score returns the capped score of a position from white's point of view,
+// from the engine's analysis or, when the game is over, from its result.
+func score(pos *pgn.Position, a *Analysis, maxScore int) int {
+	var s int
+
+	if a != nil {
+		s = a.Eval.Score()
+	} else {
+		switch pos.Outcome() {
+		case "1-0":
+			s = pgn.MateScore
+		case "0-1":
+			s = -pgn.MateScore
+		}
+	}
+
+	if maxScore > 0 {
+		s = min(max(s, -maxScore), maxScore)
+	}
+
+	return s
+}
+
+// bestLine returns the start of the engine's principal variation in SAN,
+// or nil when the engine agrees with the move played.
+func bestLine(pos *pgn.Position, a *Analysis, played string, length int) []string {
+	if length <= 0 || a == nil || a.BestMove == played {
+		return nil
+	}
+
+	pv := a.PV
+	if len(pv) > length {
+		pv = pv[:length]
+	}
+
+	var sans []string
+	for _, m := range pv {
+		san, err := pos.UCIToSAN(m)
+		if err != nil {
+			// Keep the legal start of a line the engine garbled.
+			break
+		}
+
+		sans = append(sans, san)
+		if pos, err = pos.PlayUCI(m); err != nil {
+			break
+		}
+	}
+
+	return sans
+}
@@ -0,0 +1,329 @@
+// Package uci drives chess engines that speak the Universal Chess Interface
+// protocol and uses them to annotate games.
+package uci
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Shobhit-Nagpal/pgn"
+)
+
+// Engine is a running UCI engine.
+type Engine struct {
+	Name   string
+	Author string
+
+	w     io.Writer
+	lines *bufio.Scanner
+	stdin io.Closer
+	cmd   *exec.Cmd
+}
+
+// Start runs the engine binary at path and completes the UCI handshake.
+func Start(path string, args ...string) (*Engine, error) {
+	cmd := exec.Command(path, args...)
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	e, err := NewEngine(stdout, stdin)
+	if err != nil {
+		stdin.Close()
+		cmd.Wait()
+		return nil, err
+	}
+
+	e.stdin = stdin
+	e.cmd = cmd
+
+	return e, nil
+}
+
+// NewEngine talks UCI to an engine that reads commands from w and writes
+// its replies to r, and completes the handshake. It lets an engine run in
+// any process, or in no process at all for tests.
+func NewEngine(r io.Reader, w io.Writer) (*Engine, error) {
+	e := &Engine{
+		w:     w,
+		lines: bufio.NewScanner(r),
+	}
+
+	if err := e.send("uci"); err != nil {
+		return nil, err
+	}
+
+	_, err := e.readUntil("uciok", func(line string) {
+		if name, ok := strings.CutPrefix(line, "id name "); ok {
+			e.Name = name
+		}
+		if author, ok := strings.CutPrefix(line, "id author "); ok {
+			e.Author = author
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
+
+func (e *Engine) send(command string) error {
+	_, err := io.WriteString(e.w, command+"\n")
+	return err
+}
+
+// readUntil passes every line the engine writes to fn until a line starting
+// with the given word, which it returns.
+func (e *Engine) readUntil(word string, fn func(line string)) (string, error) {
+	for e.lines.Scan() {
+		line := strings.TrimSpace(e.lines.Text())
+		if line == word || strings.HasPrefix(line, word+" ") {
+			return line, nil
+		}
+
+		if fn != nil {
+			fn(line)
+		}
+	}
+
+	if err := e.lines.Err(); err != nil {
+		return "", err
+	}
+
+	return "", fmt.Errorf("engine closed its output while waiting for %q", word)
+}
+
+// IsReady waits until the engine has processed every command sent so far.
+func (e *Engine) IsReady() error {
+	if err := e.send("isready"); err != nil {
+		return err
+	}
+
+	_, err := e.readUntil("readyok", nil)
+	return err
+}
+
+// SetOption sets an engine option such as "Hash" or "Threads".
+func (e *Engine) SetOption(name, value string) error {
+	if err := e.send(fmt.Sprintf("setoption name %s value %s", name, value)); err != nil {
+		return err
+	}
+
+	return e.IsReady()
+}
+
+// NewGame tells the engine that the next positions belong to a new game.
+func (e *Engine) NewGame() error {
+	if err := e.send("ucinewgame"); err != nil {
+		return err
+	}
+
+	return e.IsReady()
+}
+
+// Close asks the engine to quit and waits for its process to exit.
+func (e *Engine) Close() error {
+	err := e.send("quit")
+
+	if e.stdin != nil {
+		e.stdin.Close()
+	}
+
+	if e.cmd != nil {
+		if waitErr := e.cmd.Wait(); err == nil {
+			err = waitErr
+		}
+	}
+
+	return err
+}
+
+// Limit bounds a search. Zero fields are not sent; with no limit at all
+// the engine searches to depth 18.
+type Limit struct {
+	Depth    int
+	MoveTime time.Duration
+	Nodes    int
+}
+
+func (l Limit) command() string {
+	parts := []string{"go"}
+
+	if l.Depth > 0 {
+		parts = append(parts, "depth", strconv.Itoa(l.Depth))
+	}
+	if l.MoveTime > 0 {
+		parts = append(parts, "movetime", strconv.FormatInt(l.MoveTime.Milliseconds(), 10))
+	}
+	if l.Nodes > 0 {
+		parts = append(parts, "nodes", strconv.Itoa(l.Nodes))
+	}
+
+	if len(parts) == 1 {
+		parts = append(parts, "depth", "18")
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// Analysis is the result of a search.
+type Analysis struct {
+	// BestMove is the move the engine chose, in UCI notation.
+	BestMove string
+	Depth    int
+	// Eval is the evaluation of the position from white's point of view.
+	Eval pgn.Evaluation
+	// PV is the principal variation in UCI notation, starting with the best
+	// move.
+	PV []string
+}
+
+// Analyze searches the position reached by playing moves, in UCI notation,
+// from start.
+func (e *Engine) Analyze(start *pgn.Position, moves []string, limit Limit) (*Analysis, error) {
+	pos := start
+	for _, m := range moves {
+		next, err := pos.PlayUCI(m)
+		if err != nil {
+			return nil, fmt.Errorf("move %s: %v", m, err)
+		}
+		pos = next
+	}
+
+	if pos.Outcome() != "" {
+		return nil, errors.New("the game is over in this position")
+	}
+
+	fen := start.FEN()
+	if start.IsChess960() {
+		fen = start.ShredderFEN()
+	}
+
+	command := "position fen " + fen
+	if len(moves) > 0 {
+		command += " moves " + strings.Join(moves, " ")
+	}
+
+	if err := e.send(command); err != nil {
+		return nil, err
+	}
+	if err := e.send(limit.command()); err != nil {
+		return nil, err
+	}
+
+	analysis := &Analysis{}
+	var parseErr error
+
+	line, err := e.readUntil("bestmove", func(line string) {
+		if parseErr == nil && strings.HasPrefix(line, "info ") {
+			parseErr = parseInfo(line, pos.Turn(), analysis)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	if parseErr != nil {
+		return nil, parseErr
+	}
+
+	fields := strings.Fields(line)
+	if len(fields) < 2 || fields[1] == "(none)" {
+		return nil, fmt.Errorf("engine returned no best move: %q", line)
+	}
+	analysis.BestMove = fields[1]
+
+	if len(analysis.PV) == 0 || analysis.PV[0] != analysis.BestMove {
+		analysis.PV = []string{analysis.BestMove}
+	}
+
+	return analysis, nil
+}
+
+// parseInfo reads the depth, score and principal variation of an info line
+// into a. Lines for secondary variations and lines without a score are
+// ignored. Scores are turned from the side to move's point of view into
+// white's.
+func parseInfo(line string, turn pgn.Color, a *Analysis) error {
+	fields := strings.Fields(line)
+
+	var depth int
+	var eval pgn.Evaluation
+	var pv []string
+	hasScore := false
+
+	for i := 1; i < len(fields); i++ {
+		switch fields[i] {
+		case "multipv":
+			if i+1 < len(fields) && fields[i+1] != "1" {
+				return nil
+			}
+			i++
+		case "depth":
+			if i+1 < len(fields) {
+				depth, _ = strconv.Atoi(fields[i+1])
+			}
+			i++
+		case "score":
+			if i+2 >= len(fields) {
+				return fmt.Errorf("malformed score in %q", line)
+			}
+
+			n, err := strconv.Atoi(fields[i+2])
+			if err != nil {
+				return fmt.Errorf("malformed score in %q", line)
+			}
+
+			if turn == pgn.Black {
+				n = -n
+			}
+
+			switch fields[i+1] {
+			case "cp":
+				eval = pgn.Evaluation{Centipawns: n}
+			case "mate":
+				eval = pgn.Evaluation{Mate: n}
+			default:
+				return fmt.Errorf("malformed score in %q", line)
+			}
+
+			hasScore = true
+			i += 2
+		case "pv":
+			pv = fields[i+1:]
+			i = len(fields)
+		case "string":
+			i = len(fields)
+		}
+	}
+
+	if !hasScore {
+		return nil
+	}
+
+	eval.Depth = depth
+	a.Depth = depth
+	a.Eval = eval
+	if len(pv) > 0 {
+		a.PV = pv
+	}
+
+	return nil
+}
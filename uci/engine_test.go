@@ -0,0 +1,299 @@
+package uci
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Shobhit-Nagpal/pgn"
+)
+
+// scholarsMate is scripted by the number of moves in the position the
+// engine is asked to search.
+var scholarsMate = map[int][]string{
+	0: {"info depth 12 score cp 30 pv e2e4 e7e5", "bestmove e2e4 ponder e7e5"},
+	1: {"info depth 12 score cp -25 pv e7e5 g1f3", "bestmove e7e5"},
+	2: {"info depth 12 score cp 30 pv g1f3 b8c6", "bestmove g1f3"},
+	3: {"info depth 12 score cp -10 pv b8c6", "bestmove b8c6"},
+	4: {"info depth 12 score cp 15 pv f1c4", "bestmove f1c4"},
+	5: {
+		"info depth 10 multipv 2 score cp -300 pv g8f6",
+		"info depth 12 multipv 1 score cp -40 upperbound pv g7g6 h5f3 g8f6",
+		"bestmove g7g6",
+	},
+	6: {"info string mate found", "info depth 12 score mate 1 pv h5f7", "bestmove h5f7"},
+}
+
+// fakeEngine answers UCI commands read from r on w. It replies to "go" with
+// the script lines for the last position and records every command.
+type fakeEngine struct {
+	script map[int][]string
+
+	mu       sync.Mutex
+	commands []string
+}
+
+func (f *fakeEngine) run(r io.Reader, w io.Writer) {
+	moves := 0
+	lines := bufio.NewScanner(r)
+
+	for lines.Scan() {
+		line := lines.Text()
+
+		f.mu.Lock()
+		f.commands = append(f.commands, line)
+		f.mu.Unlock()
+
+		switch fields := strings.Fields(line); fields[0] {
+		case "uci":
+			fmt.Fprint(w, "id name Fake 1.0\nid author Tester\noption name Hash type spin default 16 min 1 max 1024\nuciok\n")
+		case "isready":
+			fmt.Fprint(w, "readyok\n")
+		case "position":
+			moves = 0
+			for i, field := range fields {
+				if field == "moves" {
+					moves = len(fields) - i - 1
+				}
+			}
+		case "go":
+			for _, reply := range f.script[moves] {
+				fmt.Fprintln(w, reply)
+			}
+		case "quit":
+			return
+		}
+	}
+}
+
+func (f *fakeEngine) sent(prefix string) []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	matching := []string{}
+	for _, c := range f.commands {
+		if strings.HasPrefix(c, prefix) {
+			matching = append(matching, c)
+		}
+	}
+
+	return matching
+}
+
+func startFakeEngine(t *testing.T, script map[int][]string) (*Engine, *fakeEngine) {
+	t.Helper()
+
+	commandsR, commandsW := io.Pipe()
+	repliesR, repliesW := io.Pipe()
+
+	fake := &fakeEngine{script: script}
+	go func() {
+		fake.run(commandsR, repliesW)
+		repliesW.Close()
+	}()
+
+	e, err := NewEngine(repliesR, commandsW)
+	if err != nil {
+		t.Fatalf("NewEngine() error: %v", err)
+	}
+
+	t.Cleanup(func() {
+		e.Close()
+		commandsW.Close()
+	})
+
+	return e, fake
+}
+
+func TestHandshake(t *testing.T) {
+	e, _ := startFakeEngine(t, scholarsMate)
+
+	if e.Name != "Fake 1.0" || e.Author != "Tester" {
+		t.Errorf("Name, Author = %q, %q, want %q, %q", e.Name, e.Author, "Fake 1.0", "Tester")
+	}
+
+	if err := e.SetOption("Hash", "64"); err != nil {
+		t.Errorf("SetOption() error: %v", err)
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	e, fake := startFakeEngine(t, scholarsMate)
+
+	a, err := e.Analyze(pgn.StartingPosition(), []string{"e2e4", "e7e5", "d1h5", "b8c6", "f1c4"}, Limit{Depth: 12, MoveTime: 1500 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("Analyze() error: %v", err)
+	}
+
+	if a.BestMove != "g7g6" || a.Depth != 12 {
+		t.Errorf("BestMove, Depth = %q, %d, want g7g6, 12", a.BestMove, a.Depth)
+	}
+	if a.Eval != (pgn.Evaluation{Centipawns: 40, Depth: 12}) {
+		t.Errorf("Eval = %+v, want 0.4 from white's point of view", a.Eval)
+	}
+	if strings.Join(a.PV, " ") != "g7g6 h5f3 g8f6" {
+		t.Errorf("PV = %v, want g7g6 h5f3 g8f6", a.PV)
+	}
+
+	if got := fake.sent("go"); len(got) != 1 || got[0] != "go depth 12 movetime 1500" {
+		t.Errorf("go commands = %v, want [go depth 12 movetime 1500]", got)
+	}
+
+	if _, err := e.Analyze(pgn.StartingPosition(), []string{"e2e5"}, Limit{}); err == nil {
+		t.Errorf("Analyze() with an illegal move expected error")
+	}
+}
+
+func TestAnalyzeGame(t *testing.T) {
+	e, fake := startFakeEngine(t, scholarsMate)
+
+	game, err := pgn.New(`[Result "1-0"]
+
+1. e4 e5 2. Qh5 Nc6 3. Bc4 Nf6 4. Qxf7# 1-0`)
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+
+	opts := DefaultOptions
+	opts.Limit = Limit{Depth: 12}
+
+	results, err := AnalyzeGame(e, game, opts)
+	if err != nil {
+		t.Fatalf("AnalyzeGame() error: %v", err)
+	}
+
+	if got := len(fake.sent("go")); got != 7 {
+		t.Errorf("engine searched %d positions, want 7 (not the final mate)", got)
+	}
+	if got := len(fake.sent("ucinewgame")); got != 1 {
+		t.Errorf("ucinewgame sent %d times, want 1", got)
+	}
+
+	if len(results) != 7 {
+		t.Fatalf("len(results) = %d, want 7", len(results))
+	}
+
+	expectedLoss := []int{0, 0, 20, 0, 0, 960, 0}
+	for i, r := range results {
+		if r.Loss != expectedLoss[i] {
+			t.Errorf("ply %d loss = %d, want %d", r.Ply, r.Loss, expectedLoss[i])
+		}
+	}
+
+	if results[5].NAG != "4" {
+		t.Errorf("Nf6 NAG = %q, want 4", results[5].NAG)
+	}
+	if results[6].Eval != nil {
+		t.Errorf("Eval after mate = %+v, want nil", results[6].Eval)
+	}
+
+	plies := game.Plies()
+
+	if eval, ok := plies[0].Evaluation(); !ok || eval.Centipawns != 25 || eval.Depth != 12 {
+		t.Errorf("ply 1 evaluation = %+v, %v, want 0.25 at depth 12", eval, ok)
+	}
+	if eval, ok := plies[5].Evaluation(); !ok || eval.Mate != 1 {
+		t.Errorf("ply 6 evaluation = %+v, %v, want #1", eval, ok)
+	}
+
+	blunder := plies[5]
+	if len(blunder.Annotations) != 1 || blunder.Annotations[0] != "4" {
+		t.Errorf("Nf6 annotations = %v, want [4]", blunder.Annotations)
+	}
+	if len(blunder.Variations) != 1 {
+		t.Fatalf("Nf6 variations = %d, want 1", len(blunder.Variations))
+	}
+
+	sans := []string{}
+	for _, p := range blunder.Variations[0].Plies() {
+		sans = append(sans, p.SAN)
+	}
+	if strings.Join(sans, " ") != "g6 Qf3 Nf6" {
+		t.Errorf("best line = %v, want g6 Qf3 Nf6", sans)
+	}
+
+	if len(plies[2].Annotations) != 0 || len(plies[2].Variations) != 0 {
+		t.Errorf("Qh5 should not be marked, got %v and %d variations", plies[2].Annotations, len(plies[2].Variations))
+	}
+}
+
+func TestAnalyzeGameTrustsBestMove(t *testing.T) {
+	// The second search sees the engine's own move as a blunder.
+	e, _ := startFakeEngine(t, map[int][]string{
+		0: {"info depth 12 score cp 300 pv e2e4", "bestmove e2e4"},
+		1: {"info depth 14 score cp 0 pv e7e5", "bestmove e7e5"},
+	})
+
+	game, err := pgn.New("[Result \"*\"]\n\n1. e4 *")
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+
+	results, err := AnalyzeGame(e, game, DefaultOptions)
+	if err != nil {
+		t.Fatalf("AnalyzeGame() error: %v", err)
+	}
+
+	if results[0].Loss != 0 || results[0].NAG != "" {
+		t.Errorf("e4 loss = %d, NAG = %q, want 0 and none", results[0].Loss, results[0].NAG)
+	}
+	if annotations := game.Plies()[0].Annotations; len(annotations) != 0 {
+		t.Errorf("e4 annotations = %v, want none", annotations)
+	}
+}
+
+func TestAnalyzeGameRejectsVariants(t *testing.T) {
+	e, _ := startFakeEngine(t, scholarsMate)
+
+	game, err := pgn.New("[Variant \"Atomic\"]\n[Result \"*\"]\n\n1. e4 *")
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+
+	if _, err := AnalyzeGame(e, game, DefaultOptions); err == nil {
+		t.Errorf("AnalyzeGame() on an atomic game expected error")
+	}
+}
+
+// TestFakeEngineProcess is not a real test. It runs the fake engine on the
+// standard streams when the test binary is started as an engine by
+// TestStart.
+func TestFakeEngineProcess(t *testing.T) {
+	if os.Getenv("PGN_FAKE_UCI_ENGINE") != "1" {
+		t.Skip("only runs as a subprocess")
+	}
+
+	fake := &fakeEngine{script: scholarsMate}
+	fake.run(os.Stdin, os.Stdout)
+	os.Exit(0)
+}
+
+func TestStart(t *testing.T) {
+	t.Setenv("PGN_FAKE_UCI_ENGINE", "1")
+
+	e, err := Start(os.Args[0], "-test.run=^TestFakeEngineProcess$")
+	if err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+
+	if e.Name != "Fake 1.0" {
+		t.Errorf("Name = %q, want %q", e.Name, "Fake 1.0")
+	}
+
+	a, err := e.Analyze(pgn.StartingPosition(), nil, Limit{Depth: 12})
+	if err != nil {
+		t.Fatalf("Analyze() error: %v", err)
+	}
+	if a.BestMove != "e2e4" {
+		t.Errorf("BestMove = %q, want e2e4", a.BestMove)
+	}
+
+	if err := e.Close(); err != nil {
+		t.Errorf("Close() error: %v", err)
+	}
+}
@@ -10,7 +10,7 @@ func isLetter(ch byte) bool {
 
 func isSpecialChar(ch byte) bool {
 	switch ch {
-	case '_', '+', '#', '=', ':', '-', '/':
+	case '_', '+', '#', '=', ':', '-', '/', '@', '!', '?':
 		return true
 	default:
 		return false
@@ -34,3 +34,67 @@ func isGameResult(result string) bool {
 		return false
 	}
 }
+
+var glyphNAGs = map[string]string{
+	"!":  "1",
+	"?":  "2",
+	"!!": "3",
+	"??": "4",
+	"!?": "5",
+	"?!": "6",
+}
+
+// splitGlyph separates a move from a trailing suffix annotation such as "?!"
+// and returns the annotation as the number of the equivalent NAG.
+func splitGlyph(san string) (string, string) {
+	i := len(san)
+	for i > 0 && (san[i-1] == '!' || san[i-1] == '?') {
+		i--
+	}
+
+	if nag, ok := glyphNAGs[san[i:]]; ok {
+		return san[:i], nag
+	}
+
+	return san[:i], ""
+}
+
+// nagGlyphs are the symbols conventionally printed for common NAGs.
+var nagGlyphs = map[string]string{
+	"1":   "!",
+	"2":   "?",
+	"3":   "!!",
+	"4":   "??",
+	"5":   "!?",
+	"6":   "?!",
+	"7":   "□",
+	"10":  "=",
+	"13":  "∞",
+	"14":  "⩲",
+	"15":  "⩱",
+	"16":  "±",
+	"17":  "∓",
+	"18":  "+−",
+	"19":  "−+",
+	"22":  "⨀",
+	"23":  "⨀",
+	"32":  "⟳",
+	"33":  "⟳",
+	"36":  "→",
+	"37":  "→",
+	"40":  "↑",
+	"41":  "↑",
+	"132": "⇆",
+	"133": "⇆",
+	"140": "∆",
+	"146": "N",
+}
+
+// nagGlyph returns the symbol of a NAG, or "$n" for NAGs without one.
+func nagGlyph(nag string) string {
+	if glyph, ok := nagGlyphs[nag]; ok {
+		return glyph
+	}
+
+	return "$" + nag
+}
@@ -0,0 +1,80 @@
+package pgn
+
+import (
+	"fmt"
+	"sort"
+)
+
+// SyntaxError is a problem found in PGN text, at a line and column counted
+// from 1.
+type SyntaxError struct {
+	Line    int
+	Column  int
+	Message string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("%d:%d: %s", e.Line, e.Column, e.Message)
+}
+
+// Validate reads a PGN database and returns its syntax errors and illegal
+// moves, in the order they appear. A game with syntax errors is not
+// replayed, and reading resumes with the next game. Illegal moves are
+// checked in the main line and in variations; a line is not checked past
+// its first illegal move.
+func Validate(pgn string) []*SyntaxError {
+	p := newParser(newLexer(pgn))
+	problems := []*SyntaxError{}
+
+	for !p.currTokenIs(EOF) {
+		start := p.currToken
+		game := p.parseGame(true)
+
+		if len(p.errors) > 0 {
+			problems = append(problems, p.errors...)
+			p.errors = []*SyntaxError{}
+			continue
+		}
+
+		problems = append(problems, game.illegalMoves(start)...)
+	}
+
+	sort.SliceStable(problems, func(i, j int) bool {
+		if problems[i].Line != problems[j].Line {
+			return problems[i].Line < problems[j].Line
+		}
+		return problems[i].Column < problems[j].Column
+	})
+
+	return problems
+}
+
+// illegalMoves replays the game and its variations. A bad starting position
+// is reported where the game starts.
+func (g *Game) illegalMoves(start token) []*SyntaxError {
+	pos, err := g.StartingPosition()
+	if err != nil {
+		return []*SyntaxError{{Line: start.Line, Column: start.Column, Message: err.Error()}}
+	}
+
+	return illegalMoves(pos, g.Plies())
+}
+
+func illegalMoves(pos *Position, plies []Ply) []*SyntaxError {
+	problems := []*SyntaxError{}
+
+	for _, p := range plies {
+		for _, v := range p.Variations {
+			problems = append(problems, illegalMoves(pos, v.Plies())...)
+		}
+
+		m, err := pos.parseSAN(p.SAN)
+		if err != nil {
+			return append(problems, &SyntaxError{Line: p.at.line, Column: p.at.column, Message: err.Error()})
+		}
+
+		pos = pos.play(m)
+	}
+
+	return problems
+}
@@ -0,0 +1,37 @@
+package pgn
+
+import "testing"
+
+func TestValidate(t *testing.T) {
+	input := `[Result "*"]
+
+1. e4 e5 2. Nf3 (2. Bb5 Nc6 3. Bxe8) Nc6 3. Bb5 a6 4. Ba5 *
+
+[Result "1-0"]
+1. e4 ( e5 1-0
+
+[Result "1-0"]
+1. d4 d5 1-0
+`
+
+	expected := []string{
+		`3:32: illegal move "Bxe8" in position r1bqkbnr/pppp1ppp/2n5/1B2p3/4P3/8/PPPP1PPP/RNBQK1NR w KQkq - 2 3`,
+		`3:55: illegal move "Ba5" in position r1bqkbnr/1ppp1ppp/p1n5/1B2p3/4P3/5N2/PPPP1PPP/RNBQK2R w KQkq - 0 4`,
+		`6:7: unterminated variation`,
+	}
+
+	problems := Validate(input)
+	if len(problems) != len(expected) {
+		t.Fatalf("Validate() = %v, want %d problems", problems, len(expected))
+	}
+
+	for i, problem := range problems {
+		if problem.Error() != expected[i] {
+			t.Errorf("problem %d = %q, want %q", i, problem.Error(), expected[i])
+		}
+	}
+
+	if problems := Validate("[Result \"*\"]\n\n1. e4 e5 *"); len(problems) != 0 {
+		t.Errorf("Validate() of a valid game = %v", problems)
+	}
+}
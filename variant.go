@@ -0,0 +1,535 @@
+package pgn
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Variant holds the rules of a chess variant. Replaying a game dispatches
+// move generation, move execution and the end of game conditions to the
+// variant named by its Variant tag.
+type Variant interface {
+	Name() string
+	StartingFEN() string
+
+	// legalMoves generates the legal moves of a position whose game has not
+	// ended by a variant rule.
+	legalMoves(pos *Position) []move
+	play(pos *Position, m move) *Position
+	inCheck(pos *Position) bool
+	// variantEnd returns the result when the game ended by a rule of the
+	// variant other than running out of moves, or "" otherwise.
+	variantEnd(pos *Position) string
+	// noMoves returns the result when the side to move has no legal move.
+	noMoves(pos *Position) string
+	validate(pos *Position) error
+}
+
+var (
+	Standard      Variant = standardRules{}
+	Chess960      Variant = chess960Rules{}
+	Crazyhouse    Variant = crazyhouseRules{}
+	Atomic        Variant = atomicRules{}
+	KingOfTheHill Variant = kingOfTheHillRules{}
+	ThreeCheck    Variant = threeCheckRules{}
+	Antichess     Variant = antichessRules{}
+	Horde         Variant = hordeRules{}
+)
+
+var variants = map[string]Variant{
+	"standard":      Standard,
+	"chess":         Standard,
+	"fromposition":  Standard,
+	"chess960":      Chess960,
+	"fischerandom":  Chess960,
+	"fischerrandom": Chess960,
+	"960":           Chess960,
+	"crazyhouse":    Crazyhouse,
+	"atomic":        Atomic,
+	"kingofthehill": KingOfTheHill,
+	"koth":          KingOfTheHill,
+	"threecheck":    ThreeCheck,
+	"3check":        ThreeCheck,
+	"antichess":     Antichess,
+	"giveaway":      Antichess,
+	"suicide":       Antichess,
+	"horde":         Horde,
+}
+
+// LookupVariant finds a variant by the name used in the Variant tag, ignoring
+// case, spaces and hyphens.
+func LookupVariant(name string) (Variant, bool) {
+	key := strings.ToLower(name)
+	key = strings.NewReplacer(" ", "", "-", "", "_", "").Replace(key)
+
+	if key == "" {
+		return Standard, true
+	}
+
+	v, ok := variants[key]
+	return v, ok
+}
+
+// Variant returns the rules the game is played under, from its Variant tag.
+func (g *Game) Variant() (Variant, error) {
+	v, ok := LookupVariant(g.GetTag("Variant"))
+	if !ok {
+		return nil, fmt.Errorf("unsupported variant %q", g.GetTag("Variant"))
+	}
+
+	return v, nil
+}
+
+func (pos *Position) rules() Variant {
+	if pos.variant == nil {
+		return Standard
+	}
+
+	return pos.variant
+}
+
+func (pos *Position) Variant() Variant {
+	return pos.rules()
+}
+
+// Outcome returns the result of the game if it is over in this position,
+// by checkmate, stalemate or a variant rule, and "" otherwise.
+func (pos *Position) Outcome() string {
+	rules := pos.rules()
+
+	if result := rules.variantEnd(pos); result != "" {
+		return result
+	}
+
+	if len(rules.legalMoves(pos)) == 0 {
+		return rules.noMoves(pos)
+	}
+
+	return ""
+}
+
+func winFor(c Color) string {
+	if c == White {
+		return "1-0"
+	}
+
+	return "0-1"
+}
+
+func (pos *Position) isCapture(m move) bool {
+	if m.drop != NoPieceType || m.castling {
+		return false
+	}
+
+	if !pos.board[m.to].IsEmpty() {
+		return true
+	}
+
+	return pos.board[m.from].Type == Pawn && m.from.File() != m.to.File()
+}
+
+// Standard chess
+
+type standardRules struct{}
+
+func (standardRules) Name() string {
+	return "Standard"
+}
+
+func (standardRules) StartingFEN() string {
+	return StartingFEN
+}
+
+func (standardRules) legalMoves(pos *Position) []move {
+	return pos.kingSafeMoves(pos.pseudoLegalMoves())
+}
+
+func (standardRules) play(pos *Position, m move) *Position {
+	return pos.playStandard(m)
+}
+
+func (standardRules) inCheck(pos *Position) bool {
+	return pos.kingAttacked()
+}
+
+func (standardRules) variantEnd(pos *Position) string {
+	return ""
+}
+
+func (standardRules) noMoves(pos *Position) string {
+	if pos.InCheck() {
+		return winFor(pos.turn.Other())
+	}
+
+	return "1/2-1/2"
+}
+
+func (standardRules) validate(pos *Position) error {
+	if pos.kingSquare(White) == NoSquare || pos.kingSquare(Black) == NoSquare {
+		return fmt.Errorf("both sides need a king")
+	}
+
+	return nil
+}
+
+// Chess960 plays by the standard rules; the Chess960 castling rules follow
+// from the position's castling rooks.
+
+type chess960Rules struct {
+	standardRules
+}
+
+func (chess960Rules) Name() string {
+	return "Chess960"
+}
+
+// Crazyhouse
+
+type crazyhouseRules struct {
+	standardRules
+}
+
+func (crazyhouseRules) Name() string {
+	return "Crazyhouse"
+}
+
+func (crazyhouseRules) StartingFEN() string {
+	return "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR[] w KQkq - 0 1"
+}
+
+func (crazyhouseRules) legalMoves(pos *Position) []move {
+	moves := pos.pseudoLegalMoves()
+
+	for pt := Pawn; pt < King; pt++ {
+		if pos.pockets[pos.turn][pt] == 0 {
+			continue
+		}
+
+		for to := Square(0); to < 64; to++ {
+			if !pos.board[to].IsEmpty() {
+				continue
+			}
+			if pt == Pawn && (to.Rank() == 0 || to.Rank() == 7) {
+				continue
+			}
+			moves = append(moves, move{from: NoSquare, to: to, drop: pt})
+		}
+	}
+
+	return pos.kingSafeMoves(moves)
+}
+
+func (crazyhouseRules) play(pos *Position, m move) *Position {
+	us := pos.turn
+
+	if m.drop != NoPieceType {
+		next := pos.copy()
+		next.board[m.to] = Piece{Type: m.drop, Color: us}
+		next.pockets[us][m.drop]--
+		next.ep = NoSquare
+		next.halfmove++
+		if us == Black {
+			next.fullmove++
+		}
+		next.turn = us.Other()
+		return next
+	}
+
+	next := pos.playStandard(m)
+	if m.castling {
+		return next
+	}
+
+	if captured := pos.board[m.to]; !captured.IsEmpty() {
+		pt := captured.Type
+		if pos.promoted[m.to] {
+			pt = Pawn
+		}
+		next.pockets[us][pt]++
+	} else if pos.isCapture(m) {
+		next.pockets[us][Pawn]++
+	}
+
+	next.promoted[m.to] = pos.promoted[m.from] || m.promotion != NoPieceType
+	next.promoted[m.from] = false
+
+	return next
+}
+
+// Atomic chess
+
+type atomicRules struct {
+	standardRules
+}
+
+func (atomicRules) Name() string {
+	return "Atomic"
+}
+
+func (atomicRules) legalMoves(pos *Position) []move {
+	us := pos.turn
+	legal := []move{}
+
+	for _, m := range pos.pseudoLegalMoves() {
+		// Kings cannot capture, as they would explode themselves.
+		if pos.board[m.from].Type == King && pos.isCapture(m) {
+			continue
+		}
+
+		next := pos.play(m)
+		ours, theirs := next.kingSquare(us), next.kingSquare(us.Other())
+
+		switch {
+		case ours == NoSquare:
+		case theirs == NoSquare:
+			legal = append(legal, m)
+		case kingsTouch(ours, theirs):
+			legal = append(legal, m)
+		case !next.isAttacked(ours, us.Other()):
+			legal = append(legal, m)
+		}
+	}
+
+	return legal
+}
+
+func kingsTouch(a, b Square) bool {
+	df := a.File() - b.File()
+	dr := a.Rank() - b.Rank()
+
+	return df >= -1 && df <= 1 && dr >= -1 && dr <= 1
+}
+
+func (atomicRules) play(pos *Position, m move) *Position {
+	next := pos.playStandard(m)
+	if !pos.isCapture(m) {
+		return next
+	}
+
+	// The capturing piece explodes together with every piece other than a
+	// pawn around the capture square.
+	next.board[m.to] = NoPiece
+	for _, off := range kingOffsets {
+		if sq, ok := offsetSquare(m.to, off[0], off[1]); ok && next.board[sq].Type != Pawn {
+			next.board[sq] = NoPiece
+		}
+	}
+
+	for c := White; c <= Black; c++ {
+		for side := kingSide; side <= queenSide; side++ {
+			if rook := next.castling[c][side]; rook != NoSquare && next.board[rook].IsEmpty() {
+				next.castling[c][side] = NoSquare
+			}
+		}
+		if next.kingSquare(c) == NoSquare {
+			next.castling[c] = [2]Square{NoSquare, NoSquare}
+		}
+	}
+
+	return next
+}
+
+func (atomicRules) inCheck(pos *Position) bool {
+	ours, theirs := pos.kingSquare(pos.turn), pos.kingSquare(pos.turn.Other())
+	if ours == NoSquare || theirs == NoSquare || kingsTouch(ours, theirs) {
+		return false
+	}
+
+	return pos.kingAttacked()
+}
+
+func (atomicRules) variantEnd(pos *Position) string {
+	for c := White; c <= Black; c++ {
+		if pos.kingSquare(c) == NoSquare {
+			return winFor(c.Other())
+		}
+	}
+
+	return ""
+}
+
+func (atomicRules) validate(pos *Position) error {
+	return nil
+}
+
+// King of the Hill
+
+type kingOfTheHillRules struct {
+	standardRules
+}
+
+func (kingOfTheHillRules) Name() string {
+	return "King of the Hill"
+}
+
+func (kingOfTheHillRules) variantEnd(pos *Position) string {
+	for c := White; c <= Black; c++ {
+		king := pos.kingSquare(c)
+		if king == NoSquare {
+			continue
+		}
+
+		if (king.File() == 3 || king.File() == 4) && (king.Rank() == 3 || king.Rank() == 4) {
+			return winFor(c)
+		}
+	}
+
+	return ""
+}
+
+// Three-check
+
+type threeCheckRules struct {
+	standardRules
+}
+
+func (threeCheckRules) Name() string {
+	return "Three-check"
+}
+
+func (threeCheckRules) StartingFEN() string {
+	return "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 3+3 0 1"
+}
+
+func (threeCheckRules) play(pos *Position, m move) *Position {
+	next := pos.playStandard(m)
+	if next.kingAttacked() {
+		next.checks[pos.turn]++
+	}
+
+	return next
+}
+
+func (threeCheckRules) variantEnd(pos *Position) string {
+	for c := White; c <= Black; c++ {
+		if pos.checks[c] >= 3 {
+			return winFor(c)
+		}
+	}
+
+	return ""
+}
+
+// Antichess
+
+type antichessRules struct {
+	standardRules
+}
+
+func (antichessRules) Name() string {
+	return "Antichess"
+}
+
+func (antichessRules) StartingFEN() string {
+	return "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w - - 0 1"
+}
+
+// legalMoves treats the king as an ordinary piece, allows promotion to king
+// and makes captures compulsory.
+func (antichessRules) legalMoves(pos *Position) []move {
+	moves := []move{}
+	for _, m := range pos.pseudoLegalMoves() {
+		if m.castling {
+			continue
+		}
+
+		moves = append(moves, m)
+		if m.promotion == Queen {
+			moves = append(moves, move{from: m.from, to: m.to, promotion: King})
+		}
+	}
+
+	captures := []move{}
+	for _, m := range moves {
+		if pos.isCapture(m) {
+			captures = append(captures, m)
+		}
+	}
+
+	if len(captures) > 0 {
+		return captures
+	}
+
+	return moves
+}
+
+func (antichessRules) inCheck(pos *Position) bool {
+	return false
+}
+
+func (antichessRules) variantEnd(pos *Position) string {
+	for c := White; c <= Black; c++ {
+		if !pos.hasPieces(c) {
+			return winFor(c)
+		}
+	}
+
+	return ""
+}
+
+// noMoves gives the win to the side that cannot move.
+func (antichessRules) noMoves(pos *Position) string {
+	return winFor(pos.turn)
+}
+
+func (antichessRules) validate(pos *Position) error {
+	return nil
+}
+
+func (pos *Position) hasPieces(c Color) bool {
+	for sq := Square(0); sq < 64; sq++ {
+		if p := pos.board[sq]; !p.IsEmpty() && p.Color == c {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Horde
+
+type hordeRules struct {
+	standardRules
+}
+
+func (hordeRules) Name() string {
+	return "Horde"
+}
+
+func (hordeRules) StartingFEN() string {
+	return "rnbqkbnr/pppppppp/8/1PP2PP1/PPPPPPPP/PPPPPPPP/PPPPPPPP/PPPPPPPP w kq - 0 1"
+}
+
+// legalMoves adds double steps for white pawns on the first rank.
+func (hordeRules) legalMoves(pos *Position) []move {
+	moves := pos.pseudoLegalMoves()
+
+	if pos.turn == White {
+		for file := 0; file < 8; file++ {
+			from := newSquare(file, 0)
+			over, to := newSquare(file, 1), newSquare(file, 2)
+
+			if pos.board[from] == (Piece{Type: Pawn, Color: White}) && pos.board[over].IsEmpty() && pos.board[to].IsEmpty() {
+				moves = append(moves, move{from: from, to: to})
+			}
+		}
+	}
+
+	return pos.kingSafeMoves(moves)
+}
+
+func (hordeRules) variantEnd(pos *Position) string {
+	if !pos.hasPieces(White) {
+		return "0-1"
+	}
+
+	return ""
+}
+
+func (hordeRules) validate(pos *Position) error {
+	if pos.kingSquare(Black) == NoSquare {
+		return fmt.Errorf("black needs a king")
+	}
+
+	return nil
+}
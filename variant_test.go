@@ -0,0 +1,202 @@
+package pgn
+
+import "testing"
+
+func replayVariant(t *testing.T, variant string, movetext string) []*Position {
+	t.Helper()
+
+	game, err := New(`[Variant "` + variant + `"]` + "\n\n" + movetext)
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+
+	positions, err := game.Positions()
+	if err != nil {
+		t.Fatalf("Positions() error: %v", err)
+	}
+
+	return positions
+}
+
+func TestLookupVariant(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected Variant
+	}{
+		{"", Standard},
+		{"Standard", Standard},
+		{"Chess960", Chess960},
+		{"Crazyhouse", Crazyhouse},
+		{"Atomic", Atomic},
+		{"King of the Hill", KingOfTheHill},
+		{"Three-check", ThreeCheck},
+		{"Antichess", Antichess},
+		{"Horde", Horde},
+	}
+
+	for _, tt := range tests {
+		v, ok := LookupVariant(tt.name)
+		if !ok || v != tt.expected {
+			t.Errorf("LookupVariant(%q) = %v, %v, want %v", tt.name, v, ok, tt.expected.Name())
+		}
+	}
+
+	if _, ok := LookupVariant("Shogi"); ok {
+		t.Errorf("LookupVariant(%q) expected no variant", "Shogi")
+	}
+}
+
+func TestCrazyhouse(t *testing.T) {
+	positions := replayVariant(t, "Crazyhouse", "1. e4 d5 2. exd5 Qxd5 3. Nc3 Qd8 4. P@e6 fxe6 5. d4 P@f3")
+
+	expected := "rnbqkbnr/ppp1p1pp/4p3/8/3P4/2N2p2/PPP2PPP/R1BQKBNR[p] w KQkq - 1 6"
+	if got := positions[len(positions)-1].FEN(); got != expected {
+		t.Errorf("final FEN = %q, want %q", got, expected)
+	}
+
+	pos, err := ParseVariantFEN("4k3/1P6/8/8/8/8/8/4K3[] w - - 0 1", Crazyhouse)
+	if err != nil {
+		t.Fatalf("ParseVariantFEN() error: %v", err)
+	}
+
+	pos, err = pos.PlaySAN("b8=Q+")
+	if err != nil {
+		t.Fatalf("PlaySAN() error: %v", err)
+	}
+	pos, err = pos.PlaySAN("Kd7")
+	if err != nil {
+		t.Fatalf("PlaySAN() error: %v", err)
+	}
+
+	if got, want := pos.FEN(), "1Q~6/3k4/8/8/8/8/8/4K3[] w - - 1 2"; got != want {
+		t.Errorf("FEN after promotion = %q, want %q", got, want)
+	}
+
+	uci, err := positions[6].SANToUCI("P@e6")
+	if err != nil {
+		t.Fatalf("SANToUCI() error: %v", err)
+	}
+	if uci != "P@e6" {
+		t.Errorf("SANToUCI(P@e6) = %q, want %q", uci, "P@e6")
+	}
+
+	san, err := positions[6].UCIToSAN("P@e6")
+	if err != nil {
+		t.Fatalf("UCIToSAN() error: %v", err)
+	}
+	if san != "@e6" {
+		t.Errorf("UCIToSAN(P@e6) = %q, want %q", san, "@e6")
+	}
+}
+
+func TestAtomic(t *testing.T) {
+	positions := replayVariant(t, "Atomic", "1. Nf3 f6 2. Ne5 a6 3. Nxd7")
+	final := positions[len(positions)-1]
+
+	expected := "rn3bnr/1pp1p1pp/p4p2/8/8/8/PPPPPPPP/RNBQKB1R b KQ - 0 3"
+	if got := final.FEN(); got != expected {
+		t.Errorf("final FEN = %q, want %q", got, expected)
+	}
+
+	if got := final.Outcome(); got != "1-0" {
+		t.Errorf("Outcome() = %q, want %q", got, "1-0")
+	}
+}
+
+func TestKingOfTheHill(t *testing.T) {
+	positions := replayVariant(t, "King of the Hill", "1. d4 e5 2. Kd2 Ke7 3. Kd3 Ke6 4. Ke4")
+	final := positions[len(positions)-1]
+
+	if got := final.Outcome(); got != "1-0" {
+		t.Errorf("Outcome() = %q, want %q", got, "1-0")
+	}
+
+	if moves := final.LegalMoves(); len(moves) != 0 {
+		t.Errorf("LegalMoves() after the game ended = %v, want none", moves)
+	}
+}
+
+func TestThreeCheck(t *testing.T) {
+	positions := replayVariant(t, "Three-check", "1. e4 e5 2. Bc4 Nc6 3. Bxf7+ Kxf7 4. Qh5+ g6 5. Qxg6+")
+	final := positions[len(positions)-1]
+
+	if got, want := positions[5].FEN(), "r1bqkbnr/pppp1Bpp/2n5/4p3/4P3/8/PPPP1PPP/RNBQK1NR b KQkq - 2+3 0 3"; got != want {
+		t.Errorf("FEN after first check = %q, want %q", got, want)
+	}
+
+	if got := final.Outcome(); got != "1-0" {
+		t.Errorf("Outcome() = %q, want %q", got, "1-0")
+	}
+
+	pos, err := ParseVariantFEN("4k3/8/8/8/8/8/8/4K3 w - - 0 1 +2+0", ThreeCheck)
+	if err != nil {
+		t.Fatalf("ParseVariantFEN() error: %v", err)
+	}
+	if got, want := pos.FEN(), "4k3/8/8/8/8/8/8/4K3 w - - 1+3 0 1"; got != want {
+		t.Errorf("FEN() = %q, want %q", got, want)
+	}
+}
+
+func TestAntichess(t *testing.T) {
+	positions := replayVariant(t, "Antichess", "1. e3 b5")
+	final := positions[len(positions)-1]
+
+	moves := final.LegalMoves()
+	if len(moves) != 1 || moves[0] != "Bxb5" {
+		t.Errorf("LegalMoves() = %v, want only the capture Bxb5", moves)
+	}
+
+	if _, err := final.PlaySAN("Nf3"); err == nil {
+		t.Errorf("PlaySAN(Nf3) expected error when a capture is available")
+	}
+
+	pos, err := ParseVariantFEN("8/8/8/8/8/8/1p6/K7 w - - 0 1", Antichess)
+	if err != nil {
+		t.Fatalf("ParseVariantFEN() error: %v", err)
+	}
+
+	pos, err = pos.PlaySAN("Kxb2")
+	if err != nil {
+		t.Fatalf("PlaySAN(Kxb2) error: %v", err)
+	}
+
+	if got := pos.Outcome(); got != "0-1" {
+		t.Errorf("Outcome() = %q, want %q", got, "0-1")
+	}
+}
+
+func TestHorde(t *testing.T) {
+	pos, err := ParseVariantFEN(Horde.StartingFEN(), Horde)
+	if err != nil {
+		t.Fatalf("ParseVariantFEN() error: %v", err)
+	}
+
+	if moves := pos.LegalMoves(); len(moves) != 8 {
+		t.Errorf("len(LegalMoves()) at the start = %d, want 8", len(moves))
+	}
+
+	pos, err = ParseVariantFEN("4k3/8/8/8/8/8/8/P7 w - - 0 1", Horde)
+	if err != nil {
+		t.Fatalf("ParseVariantFEN() error: %v", err)
+	}
+
+	if _, err := pos.PlaySAN("a3"); err != nil {
+		t.Errorf("PlaySAN(a3) from the first rank error: %v", err)
+	}
+
+	pos, err = ParseVariantFEN("4k3/8/8/8/8/8/1r6/P7 b - - 0 1", Horde)
+	if err != nil {
+		t.Fatalf("ParseVariantFEN() error: %v", err)
+	}
+
+	for _, san := range []string{"Rb1", "a2", "Rb2", "a3", "Ra2", "a4", "Rxa4"} {
+		pos, err = pos.PlaySAN(san)
+		if err != nil {
+			t.Fatalf("PlaySAN(%q) error: %v", san, err)
+		}
+	}
+
+	if got := pos.Outcome(); got != "0-1" {
+		t.Errorf("Outcome() = %q, want %q", got, "0-1")
+	}
+}
@@ -0,0 +1,84 @@
+package pgn
+
+const (
+	polyglotCastleOffset    = 768
+	polyglotEnPassantOffset = 772
+	polyglotTurnOffset      = 780
+)
+
+// Hash returns the Zobrist key of the position. The key is computed exactly
+// as in the Polyglot opening book format, so it can be used to probe
+// Polyglot books directly.
+func (pos *Position) Hash() uint64 {
+	var h uint64
+
+	for sq := Square(0); sq < 64; sq++ {
+		p := pos.board[sq]
+		if p.IsEmpty() {
+			continue
+		}
+
+		kind := 2 * int(p.Type-Pawn)
+		if p.Color == White {
+			kind++
+		}
+
+		h ^= polyglotRandom[64*kind+8*sq.Rank()+sq.File()]
+	}
+
+	if pos.castling[White][kingSide] != NoSquare {
+		h ^= polyglotRandom[polyglotCastleOffset]
+	}
+	if pos.castling[White][queenSide] != NoSquare {
+		h ^= polyglotRandom[polyglotCastleOffset+1]
+	}
+	if pos.castling[Black][kingSide] != NoSquare {
+		h ^= polyglotRandom[polyglotCastleOffset+2]
+	}
+	if pos.castling[Black][queenSide] != NoSquare {
+		h ^= polyglotRandom[polyglotCastleOffset+3]
+	}
+
+	if pos.epCapturePossible() {
+		h ^= polyglotRandom[polyglotEnPassantOffset+pos.ep.File()]
+	}
+
+	if pos.turn == White {
+		h ^= polyglotRandom[polyglotTurnOffset]
+	}
+
+	return h
+}
+
+// epCapturePossible reports whether a pawn of the side to move stands next
+// to the pawn that just made a double step. Polyglot only hashes the en
+// passant file in that case, whether or not the capture is actually legal.
+func (pos *Position) epCapturePossible() bool {
+	if pos.ep == NoSquare {
+		return false
+	}
+
+	rank := pos.ep.Rank() - pawnDirection(pos.turn)
+	for _, df := range []int{-1, 1} {
+		file := pos.ep.File() + df
+		if file < 0 || file > 7 {
+			continue
+		}
+
+		if pos.board[newSquare(file, rank)] == (Piece{Type: Pawn, Color: pos.turn}) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// HashFEN returns the Zobrist key of the position described by fen.
+func HashFEN(fen string) (uint64, error) {
+	pos, err := ParseFEN(fen)
+	if err != nil {
+		return 0, err
+	}
+
+	return pos.Hash(), nil
+}
@@ -0,0 +1,97 @@
+package pgn
+
+import "testing"
+
+func TestPolyglotHash(t *testing.T) {
+	// Reference keys from the Polyglot book format specification.
+	tests := []struct {
+		moves    []string
+		expected uint64
+	}{
+		{[]string{}, 0x463b96181691fc9c},
+		{[]string{"e4"}, 0x823c9b50fd114196},
+		{[]string{"e4", "d5"}, 0x0756b94461c50fb0},
+		{[]string{"e4", "d5", "e5"}, 0x662fafb965db29d4},
+		{[]string{"e4", "d5", "e5", "f5"}, 0x22a48b5a8e47ff78},
+		{[]string{"e4", "d5", "e5", "f5", "Ke2"}, 0x652a607ca3f242c1},
+		{[]string{"e4", "d5", "e5", "f5", "Ke2", "Kf7"}, 0x00fdd303c946bdd9},
+		{[]string{"a4", "b5", "h4", "b4", "c4"}, 0x3c8123ea7b067637},
+		{[]string{"a4", "b5", "h4", "b4", "c4", "bxc3", "Ra3"}, 0x5c3f9b829b279560},
+	}
+
+	for _, tt := range tests {
+		pos := StartingPosition()
+		for _, san := range tt.moves {
+			next, err := pos.PlaySAN(san)
+			if err != nil {
+				t.Fatalf("PlaySAN(%q) error: %v", san, err)
+			}
+			pos = next
+		}
+
+		if got := pos.Hash(); got != tt.expected {
+			t.Errorf("Hash() after %v = %016x, want %016x", tt.moves, got, tt.expected)
+		}
+	}
+}
+
+func TestPositionIndex(t *testing.T) {
+	input := `[Event "A"]
+[Result "*"]
+
+1. e4 e5 2. Nf3 Nc6 3. Bc4 *
+
+[Event "B"]
+[Result "1-0"]
+
+1. Nf3 Nc6 2. e4 e5 3. d4 1-0
+
+[Event "C"]
+[Result "0-1"]
+
+1. d4 d5 0-1`
+
+	games, err := NewGames(input)
+	if err != nil {
+		t.Fatalf("NewGames() error: %v", err)
+	}
+
+	if len(games) != 3 {
+		t.Fatalf("len(NewGames()) = %d, want 3", len(games))
+	}
+
+	idx := NewPositionIndex()
+	for _, g := range games {
+		if _, err := idx.Add(g); err != nil {
+			t.Fatalf("Add() error: %v", err)
+		}
+	}
+
+	fen := "r1bqkbnr/pppp1ppp/2n5/4p3/4P3/5N2/PPPP1PPP/RNBQKB1R w KQkq - 2 3"
+	hits, err := idx.Lookup(fen)
+	if err != nil {
+		t.Fatalf("Lookup() error: %v", err)
+	}
+
+	expected := []PositionHit{{Game: 0, Ply: 4}, {Game: 1, Ply: 4}}
+	if len(hits) != len(expected) {
+		t.Fatalf("Lookup() = %v, want %v", hits, expected)
+	}
+	for i := range expected {
+		if hits[i] != expected[i] {
+			t.Errorf("Lookup()[%d] = %v, want %v", i, hits[i], expected[i])
+		}
+	}
+
+	ids, err := idx.Games(StartingFEN)
+	if err != nil {
+		t.Fatalf("Games() error: %v", err)
+	}
+	if len(ids) != 3 {
+		t.Errorf("Games(StartingFEN) = %v, want all 3 games", ids)
+	}
+
+	if got := idx.Game(1).Event(); got != "B" {
+		t.Errorf("Game(1).Event() = %q, want %q", got, "B")
+	}
+}